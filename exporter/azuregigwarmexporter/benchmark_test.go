@@ -0,0 +1,394 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build cgo
+
+package azuregigwarmexporter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+
+	cgogeneva "github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter/internal/cgo"
+)
+
+// The benchmarks report, besides ns/op, B/op and allocs/op:
+//   - MB/s: OTLP request bytes processed per second
+//   - ns/record: time per log record or span
+//   - B/record: encoded (compressed) bytes per record for encode benchmarks, OTLP bytes
+//     per record otherwise
+//   - cgocalls/op: calls from Go into C per operation
+//
+// BenchmarkUploadBatch uploads to a local stub of the Geneva config service and
+// ingestion gateway, and needs the Rust bridge built with the mock_auth Cargo feature.
+// Compare runs with benchstat, e.g.
+//
+//	go test -run '^$' -bench . -count 10 . > new.txt
+
+// benchShape is a payload shape: the number of records, the size of each body (or span
+// name) and the number of attributes per record.
+type benchShape struct {
+	name     string
+	records  int
+	bodySize int
+	attrs    int
+}
+
+var benchShapes = []benchShape{
+	{name: "ManySmall", records: 10000, bodySize: 64, attrs: 3},
+	{name: "FewHuge", records: 10, bodySize: 256 << 10, attrs: 3},
+	{name: "AttributeHeavy", records: 1000, bodySize: 64, attrs: 100},
+}
+
+func benchLogs(shape benchShape) plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "benchmark")
+	rl.Resource().Attributes().PutStr("host.name", "bench-host")
+	records := rl.ScopeLogs().AppendEmpty().LogRecords()
+	records.EnsureCapacity(shape.records)
+	body := strings.Repeat("x", shape.bodySize)
+	now := pcommon.NewTimestampFromTime(time.Now())
+	for i := 0; i < shape.records; i++ {
+		lr := records.AppendEmpty()
+		lr.SetTimestamp(now)
+		lr.SetObservedTimestamp(now)
+		lr.SetSeverityNumber(plog.SeverityNumberInfo)
+		lr.SetSeverityText("INFO")
+		lr.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, byte(i)})
+		lr.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, byte(i)})
+		lr.Body().SetStr(body)
+		for a := 0; a < shape.attrs; a++ {
+			lr.Attributes().PutStr("attr_"+strconv.Itoa(a), "value-"+strconv.Itoa(i))
+		}
+	}
+	return ld
+}
+
+func benchTraces(shape benchShape) ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "benchmark")
+	rs.Resource().Attributes().PutStr("host.name", "bench-host")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	spans.EnsureCapacity(shape.records)
+	name := strings.Repeat("s", shape.bodySize)
+	start := time.Now()
+	for i := 0; i < shape.records; i++ {
+		span := spans.AppendEmpty()
+		span.SetName(name)
+		span.SetKind(ptrace.SpanKindServer)
+		span.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, byte(i)})
+		span.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, byte(i >> 8), byte(i)})
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(time.Millisecond)))
+		for a := 0; a < shape.attrs; a++ {
+			span.Attributes().PutStr("attr_"+strconv.Itoa(a), "value-"+strconv.Itoa(i))
+		}
+	}
+	return td
+}
+
+// reportPerRecord reports ns/record, B/record and cgocalls/op for a benchmark that
+// processed records per operation. cgoBefore is runtime.NumCgoCall before the loop.
+func reportPerRecord(b *testing.B, records, bytesPerOp int, cgoBefore int64) {
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*records), "ns/record")
+	b.ReportMetric(float64(bytesPerOp)/float64(records), "B/record")
+	b.ReportMetric(float64(runtime.NumCgoCall()-cgoBefore)/float64(b.N), "cgocalls/op")
+}
+
+func BenchmarkMarshalProtoLogs(b *testing.B) {
+	for _, shape := range benchShapes {
+		b.Run(shape.name, func(b *testing.B) {
+			req := plogotlp.NewExportRequestFromLogs(benchLogs(shape))
+			data, err := req.MarshalProto()
+			require.NoError(b, err)
+
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			cgoBefore := runtime.NumCgoCall()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := req.MarshalProto(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			reportPerRecord(b, shape.records, len(data), cgoBefore)
+		})
+	}
+}
+
+func BenchmarkMarshalProtoTraces(b *testing.B) {
+	for _, shape := range benchShapes {
+		b.Run(shape.name, func(b *testing.B) {
+			req := ptraceotlp.NewExportRequestFromTraces(benchTraces(shape))
+			data, err := req.MarshalProto()
+			require.NoError(b, err)
+
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			cgoBefore := runtime.NumCgoCall()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := req.MarshalProto(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			reportPerRecord(b, shape.records, len(data), cgoBefore)
+		})
+	}
+}
+
+func BenchmarkEncodeAndCompressLogs(b *testing.B) {
+	client := newBenchClient(b, "http://127.0.0.1:1")
+	for _, shape := range benchShapes {
+		b.Run(shape.name, func(b *testing.B) {
+			data, err := plogotlp.NewExportRequestFromLogs(benchLogs(shape)).MarshalProto()
+			require.NoError(b, err)
+			benchmarkEncode(b, shape, data, client.EncodeAndCompressLogs)
+		})
+	}
+}
+
+func BenchmarkEncodeAndCompressSpans(b *testing.B) {
+	client := newBenchClient(b, "http://127.0.0.1:1")
+	for _, shape := range benchShapes {
+		b.Run(shape.name, func(b *testing.B) {
+			data, err := ptraceotlp.NewExportRequestFromTraces(benchTraces(shape)).MarshalProto()
+			require.NoError(b, err)
+			benchmarkEncode(b, shape, data, client.EncodeAndCompressSpans)
+		})
+	}
+}
+
+func benchmarkEncode(b *testing.B, shape benchShape, data []byte, encode func([]byte) (*cgogeneva.EncodedBatches, error)) {
+	batches, err := encode(data)
+	require.NoError(b, err)
+	encoded := encodedSize(b, batches)
+	batches.Close()
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	cgoBefore := runtime.NumCgoCall()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batches, err := encode(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		batches.Close()
+	}
+	b.StopTimer()
+	reportPerRecord(b, shape.records, encoded, cgoBefore)
+}
+
+// encodedSize returns the total compressed size of batches.
+func encodedSize(b *testing.B, batches *cgogeneva.EncodedBatches) int {
+	size := 0
+	for i := 0; i < batches.Len(); i++ {
+		info, err := batches.Batch(i)
+		require.NoError(b, err)
+		size += len(info.Data)
+	}
+	return size
+}
+
+func BenchmarkUploadBatch(b *testing.B) {
+	stub := newGenevaStub(b)
+	client := newBenchClient(b, stub.URL)
+	for _, shape := range benchShapes {
+		b.Run(shape.name, func(b *testing.B) {
+			data, err := plogotlp.NewExportRequestFromLogs(benchLogs(shape)).MarshalProto()
+			require.NoError(b, err)
+			batches, err := client.EncodeAndCompressLogs(data)
+			require.NoError(b, err)
+			defer batches.Close()
+			encoded := encodedSize(b, batches)
+			if err := client.UploadBatch(batches, 0); err != nil {
+				b.Skipf("upload to the local stub failed (is the bridge built with mock_auth?): %v", err)
+			}
+
+			b.SetBytes(int64(encoded))
+			b.ReportAllocs()
+			cgoBefore := runtime.NumCgoCall()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < batches.Len(); j++ {
+					if err := client.UploadBatch(batches, j); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			b.StopTimer()
+			reportPerRecord(b, shape.records, encoded, cgoBefore)
+		})
+	}
+}
+
+// BenchmarkPushLogs measures the whole logs pipeline, from the pdata transformations to
+// encoding, with dry_run so that nothing is uploaded.
+func BenchmarkPushLogs(b *testing.B) {
+	exp, err := newLogsExporter(context.Background(), newBenchSettings(), newBenchConfig("http://127.0.0.1:1"))
+	require.NoError(b, err)
+	b.Cleanup(func() { _ = exp.shutdown(context.Background()) })
+	for _, shape := range benchShapes {
+		b.Run(shape.name, func(b *testing.B) {
+			ld := benchLogs(shape)
+			size := (&plog.ProtoMarshaler{}).LogsSize(ld)
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			cgoBefore := runtime.NumCgoCall()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// pushLogs mutates its input
+				b.StopTimer()
+				in := plog.NewLogs()
+				ld.CopyTo(in)
+				b.StartTimer()
+				if err := exp.pushLogs(context.Background(), in); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			reportPerRecord(b, shape.records, size, cgoBefore)
+		})
+	}
+}
+
+// BenchmarkPushTraces measures the whole traces pipeline, from the pdata transformations
+// to encoding, with dry_run so that nothing is uploaded.
+func BenchmarkPushTraces(b *testing.B) {
+	exp, err := newTracesExporter(context.Background(), newBenchSettings(), newBenchConfig("http://127.0.0.1:1"))
+	require.NoError(b, err)
+	b.Cleanup(func() { _ = exp.shutdown(context.Background()) })
+	for _, shape := range benchShapes {
+		b.Run(shape.name, func(b *testing.B) {
+			td := benchTraces(shape)
+			size := (&ptrace.ProtoMarshaler{}).TracesSize(td)
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			cgoBefore := runtime.NumCgoCall()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// pushTraces mutates its input
+				b.StopTimer()
+				in := ptrace.NewTraces()
+				td.CopyTo(in)
+				b.StartTimer()
+				if err := exp.pushTraces(context.Background(), in); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			reportPerRecord(b, shape.records, size, cgoBefore)
+		})
+	}
+}
+
+func newBenchConfig(endpoint string) *Config {
+	cfg := (&factory{}).createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint
+	cfg.Environment = "bench"
+	cfg.Account = "bench"
+	cfg.Namespace = "bench"
+	cfg.Region = "local"
+	cfg.ConfigMajorVersion = 1
+	cfg.Tenant = "bench-tenant"
+	cfg.RoleName = "bench-role"
+	cfg.RoleInstance = "bench-instance"
+	cfg.DryRun = true
+	return cfg
+}
+
+func newBenchSettings() exporter.Settings {
+	return exporter.Settings{
+		ID: component.NewID(Type),
+		TelemetrySettings: component.TelemetrySettings{
+			Logger:        zap.NewNop(),
+			MeterProvider: noop.NewMeterProvider(),
+		},
+	}
+}
+
+func newBenchClient(b *testing.B, endpoint string) *cgogeneva.GenevaClient {
+	cfg := newBenchConfig(endpoint)
+	client, err := cgogeneva.NewGenevaClient(cgogeneva.GenevaConfig{
+		Endpoint:           cfg.Endpoint,
+		Environment:        cfg.Environment,
+		Account:            cfg.Account,
+		Namespace:          cfg.Namespace,
+		Region:             cfg.Region,
+		ConfigMajorVersion: cfg.ConfigMajorVersion,
+		Tenant:             cfg.Tenant,
+		RoleName:           cfg.RoleName,
+		RoleInstance:       cfg.RoleInstance,
+	})
+	require.NoError(b, err)
+	b.Cleanup(client.Close)
+	return client
+}
+
+// newGenevaStub starts a minimal Geneva config service and ingestion gateway that
+// accepts every upload.
+func newGenevaStub(b *testing.B) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != stubIngestPath {
+			writeStubConfig(w, srv.URL)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"ticket":"bench"}`))
+	}))
+	b.Cleanup(srv.Close)
+	return srv
+}
+
+// stubIngestPath is the upload path of the ingestion gateway, as served by
+// testbed/genevaemulator.
+const stubIngestPath = "/api/v1/ingestion/ingest"
+
+// writeStubConfig answers a Geneva config request with endpoint as the ingestion
+// gateway and an unsigned token that the mock_auth bridge accepts.
+func writeStubConfig(w http.ResponseWriter, endpoint string) {
+	expiry := time.Now().Add(time.Hour)
+	claims, _ := json.Marshal(map[string]any{"Endpoint": endpoint, "exp": expiry.Unix()})
+	token := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(claims) + "."
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"IngestionGatewayInfo": map[string]string{
+			"Endpoint":            endpoint,
+			"AuthToken":           token,
+			"AuthTokenExpiryTime": expiry.UTC().Format(time.RFC3339),
+		},
+		"StorageAccountKeys": []map[string]any{{
+			"AccountMonikerName": "stubdiagaccount",
+			"AccountGroupName":   "stubdiagaccountgroup",
+			"IsPrimaryMoniker":   true,
+		}},
+		"TagId": "stub",
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	// BodyModeRaw passes structured log bodies to the encoder unchanged.
+	BodyModeRaw = "raw"
+	// BodyModeJSONString replaces map and slice bodies with their JSON encoding.
+	BodyModeJSONString = "json_string"
+	// BodyModeFlatten expands map bodies into attributes (columns).
+	BodyModeFlatten = "flatten"
+
+	defaultBodyFlattenPrefix   = "body."
+	defaultBodyFlattenMaxDepth = 3
+)
+
+// BodyFlattenConfig configures the "flatten" body mode.
+type BodyFlattenConfig struct {
+	// Prefix is prepended to every flattened key (default: "body."). It must start with a
+	// letter and contain only letters, digits, "_" and ".".
+	Prefix string `mapstructure:"prefix"`
+	// MaxDepth is the number of nested map levels that are expanded. Deeper values are
+	// stored as JSON strings (default: 3).
+	MaxDepth int `mapstructure:"max_depth"`
+}
+
+// defaultBodyFlattenConfig returns the default "flatten" body mode configuration.
+func defaultBodyFlattenConfig() BodyFlattenConfig {
+	return BodyFlattenConfig{Prefix: defaultBodyFlattenPrefix}
+}
+
+// validateBodyMode checks if the body mode configuration is valid.
+func validateBodyMode(mode string, flatten BodyFlattenConfig) error {
+	switch mode {
+	case "", BodyModeRaw, BodyModeJSONString, BodyModeFlatten:
+	default:
+		return fmt.Errorf(`invalid body_mode %q (must be %q, %q or %q)`, mode, BodyModeRaw, BodyModeJSONString, BodyModeFlatten)
+	}
+	if flatten.MaxDepth < 0 {
+		return fmt.Errorf(`invalid body_flatten::max_depth: %d (must be >= 0)`, flatten.MaxDepth)
+	}
+	if mode != BodyModeFlatten {
+		if flatten != (BodyFlattenConfig{}) && flatten != defaultBodyFlattenConfig() {
+			return errors.New(`"body_flatten" requires body_mode == "flatten"`)
+		}
+		return nil
+	}
+	if flatten.Prefix == "" {
+		return errors.New(`body_flatten::prefix must not be empty`)
+	}
+	if !isValidBodyFlattenPrefix(flatten.Prefix) {
+		return fmt.Errorf(`invalid body_flatten::prefix %q (must start with a letter and contain only letters, digits, "_" and ".")`, flatten.Prefix)
+	}
+	return nil
+}
+
+// isValidBodyFlattenPrefix reports whether prefix can start a flattened key: a Geneva
+// name, optionally with "." separators.
+func isValidBodyFlattenPrefix(prefix string) bool {
+	for i, r := range prefix {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '_' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return prefix != ""
+}
+
+// bodyTransformer rewrites structured log bodies according to the body mode.
+type bodyTransformer struct {
+	mode     string
+	prefix   string
+	maxDepth int
+}
+
+// newBodyTransformer creates a transformer for the mode, or returns nil for "raw".
+func newBodyTransformer(mode string, flatten BodyFlattenConfig) *bodyTransformer {
+	if mode == "" || mode == BodyModeRaw {
+		return nil
+	}
+	t := &bodyTransformer{mode: mode, prefix: flatten.Prefix, maxDepth: flatten.MaxDepth}
+	if t.maxDepth == 0 {
+		t.maxDepth = defaultBodyFlattenMaxDepth
+	}
+	return t
+}
+
+// transformLogs rewrites the body of every log record in ld. Scalar bodies are unchanged.
+func (t *bodyTransformer) transformLogs(ld plog.Logs) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				t.transform(records.At(k))
+			}
+		}
+	}
+}
+
+// transform rewrites the body of a single log record.
+func (t *bodyTransformer) transform(lr plog.LogRecord) {
+	body := lr.Body()
+	switch body.Type() {
+	case pcommon.ValueTypeMap:
+		if t.mode == BodyModeFlatten {
+			t.flatten(body.Map(), lr.Attributes())
+			body.SetStr("")
+			return
+		}
+		body.SetStr(body.AsString())
+	case pcommon.ValueTypeSlice:
+		// Slices have no keys to expand, so both modes store them as JSON.
+		body.SetStr(body.AsString())
+	}
+}
+
+// flatten expands body into attrs. Keys are visited in sorted order at every level and a
+// key that is already taken, by an existing attribute or an earlier flattened key, gets
+// the first free "_<n>" suffix (n >= 2), so the result does not depend on map ordering.
+func (t *bodyTransformer) flatten(body, attrs pcommon.Map) {
+	var walk func(m pcommon.Map, prefix string, depth int)
+	walk = func(m pcommon.Map, prefix string, depth int) {
+		keys := make([]string, 0, m.Len())
+		m.Range(func(k string, _ pcommon.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		sort.Strings(keys)
+		for _, k := range keys {
+			v, _ := m.Get(k)
+			key := prefix + k
+			if v.Type() == pcommon.ValueTypeMap && depth < t.maxDepth {
+				walk(v.Map(), key+".", depth+1)
+				continue
+			}
+			key = freeAttributeKey(attrs, key)
+			switch v.Type() {
+			case pcommon.ValueTypeMap, pcommon.ValueTypeSlice:
+				attrs.PutStr(key, v.AsString())
+			default:
+				v.CopyTo(attrs.PutEmpty(key))
+			}
+		}
+	}
+	walk(body, t.prefix, 1)
+}
+
+// freeAttributeKey returns key if it is not set in attrs, otherwise the first of
+// key_2, key_3, ... that is not set.
+func freeAttributeKey(attrs pcommon.Map, key string) string {
+	if _, ok := attrs.Get(key); !ok {
+		return key
+	}
+	for n := 2; ; n++ {
+		candidate := key + "_" + strconv.Itoa(n)
+		if _, ok := attrs.Get(candidate); !ok {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestBodyTransformer(t *testing.T) {
+	body := map[string]any{
+		"msg": "hello",
+		"http": map[string]any{
+			"status": int64(200),
+			"req": map[string]any{
+				"headers": map[string]any{"accept": "*/*"},
+			},
+		},
+		"tags": []any{"a", "b"},
+	}
+
+	tests := []struct {
+		name      string
+		mode      string
+		flatten   BodyFlattenConfig
+		attrs     map[string]any
+		body      any
+		wantBody  string
+		wantAttrs map[string]any
+	}{
+		{
+			name:      "JSONStringMap",
+			mode:      BodyModeJSONString,
+			body:      map[string]any{"b": int64(1), "a": "x"},
+			wantBody:  `{"a":"x","b":1}`,
+			wantAttrs: map[string]any{},
+		},
+		{
+			name:      "SliceAsJSON",
+			mode:      BodyModeFlatten,
+			flatten:   defaultBodyFlattenConfig(),
+			body:      []any{"a", int64(1)},
+			wantBody:  `["a",1]`,
+			wantAttrs: map[string]any{},
+		},
+		{
+			name:      "ScalarUnchanged",
+			mode:      BodyModeFlatten,
+			flatten:   defaultBodyFlattenConfig(),
+			body:      "plain",
+			wantBody:  "plain",
+			wantAttrs: map[string]any{},
+		},
+		{
+			name:     "FlattenDefaultDepth",
+			mode:     BodyModeFlatten,
+			flatten:  defaultBodyFlattenConfig(),
+			body:     body,
+			wantBody: "",
+			wantAttrs: map[string]any{
+				"body.msg":              "hello",
+				"body.http.status":      int64(200),
+				"body.http.req.headers": `{"accept":"*/*"}`,
+				"body.tags":             `["a","b"]`,
+			},
+		},
+		{
+			name:     "FlattenMaxDepthAndPrefix",
+			mode:     BodyModeFlatten,
+			flatten:  BodyFlattenConfig{Prefix: "b_", MaxDepth: 1},
+			body:     body,
+			wantBody: "",
+			wantAttrs: map[string]any{
+				"b_msg":  "hello",
+				"b_http": `{"req":{"headers":{"accept":"*/*"}},"status":200}`,
+				"b_tags": `["a","b"]`,
+			},
+		},
+		{
+			name:    "FlattenCollisions",
+			mode:    BodyModeFlatten,
+			flatten: defaultBodyFlattenConfig(),
+			attrs: map[string]any{
+				"body.msg":   "existing",
+				"body.msg_2": "existing",
+			},
+			body: map[string]any{
+				"msg": "hello",
+				"a":   map[string]any{"b": int64(1)},
+				"a.b": int64(2),
+			},
+			wantBody: "",
+			wantAttrs: map[string]any{
+				"body.msg":   "existing",
+				"body.msg_2": "existing",
+				"body.msg_3": "hello",
+				"body.a.b":   int64(1),
+				"body.a.b_2": int64(2),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, validateBodyMode(tt.mode, tt.flatten))
+			bt := newBodyTransformer(tt.mode, tt.flatten)
+			require.NotNil(t, bt)
+
+			ld := plog.NewLogs()
+			lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+			require.NoError(t, lr.Attributes().FromRaw(tt.attrs))
+			require.NoError(t, lr.Body().FromRaw(tt.body))
+
+			bt.transformLogs(ld)
+			assert.Equal(t, tt.wantBody, lr.Body().Str())
+			assert.Equal(t, tt.wantAttrs, lr.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestValidateBodyMode(t *testing.T) {
+	assert.Nil(t, newBodyTransformer(BodyModeRaw, BodyFlattenConfig{}))
+	assert.NoError(t, validateBodyMode("", BodyFlattenConfig{}))
+	assert.ErrorContains(t, validateBodyMode("yaml", BodyFlattenConfig{}), `invalid body_mode "yaml"`)
+	assert.ErrorContains(t, validateBodyMode(BodyModeFlatten, BodyFlattenConfig{MaxDepth: -1}), "must be >= 0")
+	assert.ErrorContains(t, validateBodyMode(BodyModeJSONString, BodyFlattenConfig{Prefix: "x."}), `requires body_mode == "flatten"`)
+	assert.NoError(t, validateBodyMode(BodyModeRaw, defaultBodyFlattenConfig()), "the defaults are accepted in every mode")
+
+	assert.NoError(t, validateBodyMode(BodyModeFlatten, BodyFlattenConfig{Prefix: "Body_v2.payload."}))
+	assert.ErrorContains(t, validateBodyMode(BodyModeFlatten, BodyFlattenConfig{}), "body_flatten::prefix must not be empty")
+	for _, prefix := range []string{".body", "1body.", "_body", "body-", "body ", "bödy."} {
+		assert.ErrorContains(t, validateBodyMode(BodyModeFlatten, BodyFlattenConfig{Prefix: prefix}), "invalid body_flatten::prefix", prefix)
+	}
+}
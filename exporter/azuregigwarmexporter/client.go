@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build cgo
+
+package azuregigwarmexporter
+
+import (
+	"context"
+	"errors"
+
+	cgogeneva "github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter/internal/cgo"
+)
+
+// encodedBatches is the part of *cgogeneva.EncodedBatches used by the exporters.
+type encodedBatches interface {
+	Len() int
+	Batch(idx int) (cgogeneva.BatchInfo, error)
+	Size() (int, error)
+	Close()
+}
+
+// genevaClient is the part of *cgogeneva.GenevaClient used by the exporters, so that tests
+// can run the exporters without the Rust bridge.
+type genevaClient interface {
+	EncodeAndCompressLogs(data []byte) (encodedBatches, error)
+	EncodeAndCompressSpans(data []byte) (encodedBatches, error)
+	EncodeAndCompressSpansAs(data []byte, eventName string) (encodedBatches, error)
+	UploadBatch(b encodedBatches, idx int) error
+	Shutdown(ctx context.Context) error
+}
+
+// ffiClient adapts *cgogeneva.GenevaClient to genevaClient.
+type ffiClient struct {
+	client *cgogeneva.GenevaClient
+}
+
+func (c ffiClient) EncodeAndCompressLogs(data []byte) (encodedBatches, error) {
+	return asEncodedBatches(c.client.EncodeAndCompressLogs(data))
+}
+
+func (c ffiClient) EncodeAndCompressSpans(data []byte) (encodedBatches, error) {
+	return asEncodedBatches(c.client.EncodeAndCompressSpans(data))
+}
+
+func (c ffiClient) EncodeAndCompressSpansAs(data []byte, eventName string) (encodedBatches, error) {
+	return asEncodedBatches(c.client.EncodeAndCompressSpansAs(data, eventName))
+}
+
+func (c ffiClient) UploadBatch(b encodedBatches, idx int) error {
+	batches, ok := b.(*cgogeneva.EncodedBatches)
+	if !ok {
+		return errors.New("batches were not encoded by the Geneva FFI client")
+	}
+	return c.client.UploadBatch(batches, idx)
+}
+
+func (c ffiClient) Shutdown(ctx context.Context) error {
+	return c.client.Shutdown(ctx)
+}
+
+// asEncodedBatches converts the result of an encoder call, so that a failed call returns a
+// nil interface rather than an interface holding a nil pointer.
+func asEncodedBatches(b *cgogeneva.EncodedBatches, err error) (encodedBatches, error) {
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
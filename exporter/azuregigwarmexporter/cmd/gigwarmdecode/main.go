@@ -0,0 +1,213 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Command gigwarmdecode decodes Geneva batches produced by the azuregigwarm exporter, for
+// example the payloads written by the exporter's debug_dump_dir option, and prints their
+// schemas and rows as JSON or as a table.
+//
+// Usage:
+//
+//	gigwarmdecode [-format json|table] [-raw] [-schemas] FILE...
+//
+// FILE is a compressed payload (.bin) or its JSON sidecar, in which case the payload next
+// to it is decoded. Use "-" to read a payload from standard input.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter/genevapayload"
+)
+
+func main() {
+	format := flag.String("format", "json", `output format: "json" or "table"`)
+	raw := flag.Bool("raw", false, "input is already decompressed")
+	schemasOnly := flag.Bool("schemas", false, "print schemas only")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] FILE...\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 || (*format != "json" && *format != "table") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		if err := decodeFile(os.Stdout, path, *format, *raw, *schemasOnly); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// decodeFile decodes one payload and writes it to w.
+func decodeFile(w io.Writer, path, format string, raw, schemasOnly bool) error {
+	data, err := readPayload(path)
+	if err != nil {
+		return err
+	}
+	if !raw {
+		if data, err = genevapayload.Decompress(data); err != nil {
+			return fmt.Errorf("failed to decompress (use -raw for uncompressed input): %w", err)
+		}
+	}
+	b, err := genevapayload.ParseBlob(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+	doc := newDocument(path, b, schemasOnly)
+	if format == "table" {
+		return writeTable(w, doc)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// readPayload reads a payload file, standard input, or the payload a sidecar points to.
+func readPayload(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	if strings.HasSuffix(path, ".json") {
+		sidecarData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var sidecar struct {
+			PayloadFile string `json:"payload_file"`
+		}
+		if err := json.Unmarshal(sidecarData, &sidecar); err != nil {
+			return nil, fmt.Errorf("invalid sidecar: %w", err)
+		}
+		if sidecar.PayloadFile == "" {
+			return nil, errors.New(`sidecar has no "payload_file"`)
+		}
+		path = filepath.Join(filepath.Dir(path), filepath.Base(sidecar.PayloadFile))
+	}
+	return os.ReadFile(path)
+}
+
+// document is the decoded form of one payload.
+type document struct {
+	File     string           `json:"file"`
+	Metadata string           `json:"metadata"`
+	Schemas  []schemaDocument `json:"schemas"`
+	Rows     []rowDocument    `json:"rows,omitempty"`
+}
+
+type schemaDocument struct {
+	ID      uint64                    `json:"id"`
+	MD5     string                    `json:"md5"`
+	Structs []genevapayload.StructDef `json:"structs"`
+}
+
+type rowDocument struct {
+	Event    string         `json:"event"`
+	SchemaID uint64         `json:"schema_id"`
+	Level    uint8          `json:"level"`
+	Fields   map[string]any `json:"fields,omitempty"`
+	Error    string         `json:"error,omitempty"`
+
+	columns []genevapayload.Column
+}
+
+// newDocument decodes the rows of b with their schemas. Rows that fail to decode keep
+// the fields decoded so far and report the error.
+func newDocument(path string, b *genevapayload.Blob, schemasOnly bool) document {
+	doc := document{File: path, Metadata: b.Metadata}
+	for _, s := range b.Schemas {
+		doc.Schemas = append(doc.Schemas, schemaDocument{ID: s.ID, MD5: hex.EncodeToString(s.MD5[:]), Structs: s.Def.Structs})
+	}
+	if schemasOnly {
+		return doc
+	}
+	for _, r := range b.Rows() {
+		row := rowDocument{Event: r.Event, SchemaID: r.SchemaID, Level: r.Level, columns: r.Columns}
+		if r.Err != nil {
+			row.Error = r.Err.Error()
+		}
+		if r.Columns != nil {
+			row.Fields = make(map[string]any, len(r.Columns))
+			for _, c := range r.Columns {
+				row.Fields[c.Name] = c.Value
+			}
+		}
+		doc.Rows = append(doc.Rows, row)
+	}
+	return doc
+}
+
+// writeTable prints the schemas of doc and then one table per run of rows that share an
+// event name and schema.
+func writeTable(w io.Writer, doc document) error {
+	fmt.Fprintf(w, "# %s\n# metadata: %s\n", doc.File, doc.Metadata)
+	for _, s := range doc.Schemas {
+		fmt.Fprintf(w, "# schema %d (md5 %s)\n", s.ID, s.MD5)
+		for _, st := range s.Structs {
+			fields := make([]string, 0, len(st.Fields))
+			for _, f := range st.Fields {
+				fields = append(fields, f.Name+":"+f.Type)
+			}
+			fmt.Fprintf(w, "#   %s { %s }\n", st.Name, strings.Join(fields, ", "))
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	var event string
+	var schemaID uint64
+	for i, row := range doc.Rows {
+		if i == 0 || row.Event != event || row.SchemaID != schemaID {
+			event, schemaID = row.Event, row.SchemaID
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "\n## %s (schema %d)\n", event, schemaID)
+			names := make([]string, 0, len(row.columns))
+			for _, c := range row.columns {
+				names = append(names, c.Name)
+			}
+			fmt.Fprintln(tw, strings.Join(names, "\t"))
+		}
+		values := make([]string, 0, len(row.columns))
+		for _, c := range row.columns {
+			values = append(values, formatValue(c.Value))
+		}
+		if row.Error != "" {
+			values = append(values, "ERROR: "+row.Error)
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+// formatValue renders a column value for the table output.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.NewReplacer("\t", `\t`, "\n", `\n`).Replace(v)
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
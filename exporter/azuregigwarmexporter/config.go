@@ -21,6 +21,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
@@ -73,6 +74,9 @@ type Config struct {
 	// Workload Identity auth parameters (optional; required only when AuthMethod == WorkloadIdentity)
 	WorkloadIdentityResource string `mapstructure:"workload_identity_resource"`
 
+	// TimeoutConfig bounds the duration of a single push, including all batch retries
+	TimeoutConfig exporterhelper.TimeoutConfig `mapstructure:",squash"`
+
 	// QueueConfig configures the sending queue for the exporter
 	QueueConfig exporterhelper.QueueBatchConfig `mapstructure:"sending_queue"`
 
@@ -82,10 +86,80 @@ type Config struct {
 	// BatchRetryConfig configures retry behavior for individual batch uploads
 	BatchRetryConfig BatchRetryConfig `mapstructure:"batch_retry"`
 
+	// MaxRequestBytes is the approximate maximum size of the OTLP protobuf payload passed to
+	// the encoder in one call. Larger requests are split along resource/scope boundaries and
+	// each chunk is encoded and uploaded independently (default: 0, no limit).
+	MaxRequestBytes int `mapstructure:"max_request_bytes"`
+
+	// MaxRecordsPerRequest is the maximum number of log records or spans passed to the
+	// encoder in one call (default: 0, no limit).
+	MaxRecordsPerRequest int `mapstructure:"max_records_per_request"`
+
+	// EventName configures routing of log records and spans to Geneva events based on attributes
+	EventName EventNameConfig `mapstructure:"event_name"`
+
+	// Schema maps log record and span attributes onto Geneva table columns
+	Schema SchemaConfig `mapstructure:"schema"`
+
+	// Redaction configures redaction of sensitive data before upload
+	Redaction RedactionConfig `mapstructure:"redaction"`
+
+	// Enrichment adds Geneva role and host metadata to every record
+	Enrichment EnrichmentConfig `mapstructure:"enrichment"`
+
+	// Correlation adds trace context columns to log records
+	Correlation CorrelationConfig `mapstructure:"correlation"`
+
+	// BodyMode selects how structured log bodies are encoded: "raw", "json_string" or "flatten" (default: "raw")
+	BodyMode string `mapstructure:"body_mode"`
+
+	// BodyFlatten configures the "flatten" body mode
+	BodyFlatten BodyFlattenConfig `mapstructure:"body_flatten"`
+
+	// Timestamps configures normalization of missing and out-of-range timestamps
+	Timestamps TimestampConfig `mapstructure:"timestamps"`
+
+	// SeverityPolicy keeps, drops or samples log records by severity (logs only)
+	SeverityPolicy SeverityPolicyConfig `mapstructure:"severity_policy"`
+
+	// SpanEvents configures how span events are exported (traces only)
+	SpanEvents SpanEventsConfig `mapstructure:"span_events"`
+
+	// SpanLinks configures how span links are exported (traces only)
+	SpanLinks SpanLinksConfig `mapstructure:"span_links"`
+
+	// DryRun encodes logs and spans but never uploads them. A summary of every push
+	// (records, batches, bytes) is logged instead.
+	DryRun bool `mapstructure:"dry_run"`
+
+	// DebugDump writes every encoded batch to a local directory for inspection
+	DebugDump DebugDumpConfig `mapstructure:",squash"`
+
+	// Logs overrides settings for logs; everything else is inherited
+	Logs SignalConfig `mapstructure:"logs"`
+
+	// Traces overrides settings for traces; everything else is inherited
+	Traces SignalConfig `mapstructure:"traces"`
+
 	// prevent unkeyed literal initialization
 	_ struct{}
 }
 
+// SignalConfig overrides top-level settings for one signal, e.g. to send logs and traces
+// to different Geneva namespaces. Settings that are not set are inherited from the top level.
+type SignalConfig struct {
+	// Account overrides the Geneva account
+	Account string `mapstructure:"account"`
+	// Namespace overrides the Geneva namespace
+	Namespace string `mapstructure:"namespace"`
+	// EventName overrides event name routing. Fields that are not set are inherited from
+	// the top-level event_name block.
+	EventName *EventNameConfig `mapstructure:"event_name"`
+	// BatchRetryConfig overrides batch-level retry. Fields that are not set are inherited
+	// from the top-level batch_retry block.
+	BatchRetryConfig *BatchRetryConfig `mapstructure:"batch_retry"`
+}
+
 // BatchRetryConfig configures retry behavior for individual batch uploads within a single export request.
 // This provides fine-grained retry for failed batches without re-encoding and re-uploading successful batches.
 type BatchRetryConfig struct {
@@ -136,10 +210,99 @@ func (c *BatchRetryConfig) GetMaxInterval() time.Duration {
 	return d
 }
 
-var _ component.Config = (*Config)(nil)
+var (
+	_ component.Config    = (*Config)(nil)
+	_ confmap.Unmarshaler = (*Config)(nil)
+)
+
+// Unmarshal decodes the configuration. The event_name and batch_retry blocks of the logs
+// and traces sections start from the top-level blocks, so that they only override the
+// fields they set.
+func (cfg *Config) Unmarshal(conf *confmap.Conf) error {
+	if err := conf.Unmarshal(cfg); err != nil {
+		return err
+	}
+	for _, signal := range []struct {
+		key    string
+		config *SignalConfig
+	}{
+		{"logs", &cfg.Logs},
+		{"traces", &cfg.Traces},
+	} {
+		if !conf.IsSet(signal.key) {
+			continue
+		}
+		sub, err := conf.Sub(signal.key)
+		if err != nil {
+			return err
+		}
+		if sub.IsSet("event_name") {
+			eventName := cfg.EventName
+			eventName.AllowedNames = append([]string(nil), cfg.EventName.AllowedNames...)
+			signal.config.EventName = &eventName
+		}
+		if sub.IsSet("batch_retry") {
+			batchRetry := cfg.BatchRetryConfig
+			signal.config.BatchRetryConfig = &batchRetry
+		}
+		if err := sub.Unmarshal(signal.config); err != nil {
+			return fmt.Errorf("%s: %w", signal.key, err)
+		}
+	}
+	return nil
+}
+
+// logsConfig returns the configuration of the logs exporter. Its correlation columns name
+// the events of the traces exporter, so it carries the event name routing of traces.
+func (cfg *Config) logsConfig() *Config {
+	merged := cfg.signalConfig(cfg.Logs)
+	merged.Correlation.traceEvents = cfg.tracesConfig().EventName
+	return merged
+}
+
+// tracesConfig returns the configuration of the traces exporter.
+func (cfg *Config) tracesConfig() *Config {
+	return cfg.signalConfig(cfg.Traces)
+}
 
-// Validate checks if the exporter configuration is valid
+// signalConfig returns a copy of the top-level configuration with the overrides of
+// signal applied and without per-signal sections.
+func (cfg *Config) signalConfig(signal SignalConfig) *Config {
+	merged := *cfg
+	merged.Logs = SignalConfig{}
+	merged.Traces = SignalConfig{}
+	if signal.Account != "" {
+		merged.Account = signal.Account
+	}
+	if signal.Namespace != "" {
+		merged.Namespace = signal.Namespace
+	}
+	if signal.EventName != nil {
+		merged.EventName = *signal.EventName
+	}
+	if signal.BatchRetryConfig != nil {
+		merged.BatchRetryConfig = *signal.BatchRetryConfig
+	}
+	return &merged
+}
+
+// Validate checks if the exporter configuration is valid. When logs or traces overrides are
+// set, the merged configuration of each signal is checked.
 func (cfg *Config) Validate() error {
+	if cfg.Logs == (SignalConfig{}) && cfg.Traces == (SignalConfig{}) {
+		return cfg.validate()
+	}
+	if err := cfg.logsConfig().validate(); err != nil {
+		return fmt.Errorf("logs: %w", err)
+	}
+	if err := cfg.tracesConfig().validate(); err != nil {
+		return fmt.Errorf("traces: %w", err)
+	}
+	return nil
+}
+
+// validate checks the configuration of a single signal.
+func (cfg *Config) validate() error {
 	if cfg.Endpoint == "" {
 		return errors.New(`requires a non-empty "endpoint"`)
 	}
@@ -178,5 +341,44 @@ func (cfg *Config) Validate() error {
 			return errors.New(`requires a non-empty "workload_identity_resource" when auth_method == workload_identity`)
 		}
 	}
+	if cfg.MaxRequestBytes < 0 {
+		return fmt.Errorf(`invalid max_request_bytes: %d (must be >= 0)`, cfg.MaxRequestBytes)
+	}
+	if cfg.MaxRecordsPerRequest < 0 {
+		return fmt.Errorf(`invalid max_records_per_request: %d (must be >= 0)`, cfg.MaxRecordsPerRequest)
+	}
+	if err := cfg.EventName.Validate(); err != nil {
+		return fmt.Errorf("invalid event_name: %w", err)
+	}
+	if err := cfg.Schema.Validate(); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	if err := cfg.Redaction.Validate(); err != nil {
+		return fmt.Errorf("invalid redaction: %w", err)
+	}
+	if err := cfg.Enrichment.Validate(); err != nil {
+		return fmt.Errorf("invalid enrichment: %w", err)
+	}
+	if err := cfg.Correlation.Validate(); err != nil {
+		return fmt.Errorf("invalid correlation: %w", err)
+	}
+	if err := validateBodyMode(cfg.BodyMode, cfg.BodyFlatten); err != nil {
+		return err
+	}
+	if err := cfg.Timestamps.Validate(); err != nil {
+		return fmt.Errorf("invalid timestamps: %w", err)
+	}
+	if err := cfg.SeverityPolicy.Validate(); err != nil {
+		return fmt.Errorf("invalid severity_policy: %w", err)
+	}
+	if err := cfg.DebugDump.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.SpanEvents.Validate(); err != nil {
+		return fmt.Errorf("invalid span_events: %w", err)
+	}
+	if err := cfg.SpanLinks.Validate(); err != nil {
+		return fmt.Errorf("invalid span_links: %w", err)
+	}
 	return nil
 }
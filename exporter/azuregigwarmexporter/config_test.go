@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// newTestConfigMap returns the raw configuration of an exporter with all required
+// settings and the given extra settings.
+func newTestConfigMap(extra map[string]any) map[string]any {
+	raw := map[string]any{
+		"endpoint":      "https://gcs.example.com",
+		"environment":   "Test",
+		"account":       "shared",
+		"namespace":     "Shared",
+		"region":        "westeurope",
+		"auth_method":   0,
+		"tenant":        "tenant",
+		"role_name":     "checkout",
+		"role_instance": "instance-0",
+		"event_name": map[string]any{
+			"attribute":     "service.name",
+			"template":      "App_{value}",
+			"allowed_names": []any{"App_checkout"},
+		},
+		"batch_retry": map[string]any{
+			"enabled":     true,
+			"max_retries": 3,
+			"multiplier":  2.0,
+		},
+	}
+	for k, v := range extra {
+		raw[k] = v
+	}
+	return raw
+}
+
+func unmarshalTestConfig(t *testing.T, raw map[string]any) *Config {
+	cfg := &Config{}
+	require.NoError(t, cfg.Unmarshal(confmap.NewFromStringMap(raw)))
+	return cfg
+}
+
+func TestConfigUnmarshalSignalOverrides(t *testing.T) {
+	cfg := unmarshalTestConfig(t, newTestConfigMap(map[string]any{
+		"logs": map[string]any{
+			"namespace":   "Logs",
+			"event_name":  map[string]any{"default": "AppLogs"},
+			"batch_retry": map[string]any{"max_retries": 7},
+		},
+		"traces": map[string]any{
+			"account": "traces",
+		},
+	}))
+	require.NoError(t, cfg.Validate())
+
+	logs := cfg.logsConfig()
+	assert.Equal(t, "shared", logs.Account, "not overridden")
+	assert.Equal(t, "Logs", logs.Namespace)
+	assert.Equal(t, EventNameConfig{
+		Attribute:    "service.name",
+		Template:     "App_{value}",
+		AllowedNames: []string{"App_checkout"},
+		Default:      "AppLogs",
+	}, logs.EventName, "event_name fields that are not set are inherited")
+	assert.Equal(t, 7, logs.BatchRetryConfig.MaxRetries)
+	assert.True(t, logs.BatchRetryConfig.Enabled, "batch_retry fields that are not set are inherited")
+	assert.Equal(t, 2.0, logs.BatchRetryConfig.Multiplier)
+	assert.Equal(t, SignalConfig{}, logs.Logs)
+	assert.Equal(t, SignalConfig{}, logs.Traces)
+
+	traces := cfg.tracesConfig()
+	assert.Equal(t, "traces", traces.Account)
+	assert.Equal(t, "Shared", traces.Namespace)
+	assert.Equal(t, cfg.EventName, traces.EventName)
+	assert.Equal(t, 3, traces.BatchRetryConfig.MaxRetries)
+	assert.Equal(t, "https://gcs.example.com", traces.Endpoint, "shared settings are inherited")
+
+	// The top-level blocks are not modified by the overrides
+	assert.Empty(t, cfg.EventName.Default)
+	assert.Equal(t, 3, cfg.BatchRetryConfig.MaxRetries)
+	logs.EventName.AllowedNames[0] = "Changed"
+	assert.Equal(t, []string{"App_checkout"}, cfg.EventName.AllowedNames)
+}
+
+func TestConfigLogsCorrelationTraceEvents(t *testing.T) {
+	cfg := unmarshalTestConfig(t, newTestConfigMap(map[string]any{
+		"traces": map[string]any{
+			"event_name": map[string]any{"template": "Spans_{value}", "allowed_names": []any{}},
+		},
+	}))
+	require.NoError(t, cfg.Validate())
+
+	// The correlation columns of logs name the events the traces exporter routes spans to
+	assert.Equal(t, EventNameConfig{
+		Attribute:    "service.name",
+		Template:     "Spans_{value}",
+		AllowedNames: []string{},
+	}, cfg.logsConfig().Correlation.traceEvents)
+}
+
+func TestConfigUnmarshalWithoutOverrides(t *testing.T) {
+	cfg := unmarshalTestConfig(t, newTestConfigMap(nil))
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, SignalConfig{}, cfg.Logs)
+	assert.Equal(t, SignalConfig{}, cfg.Traces)
+	assert.Equal(t, "Shared", cfg.logsConfig().Namespace)
+	assert.Equal(t, "Shared", cfg.tracesConfig().Namespace)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   map[string]any
+		wantErr string
+	}{
+		{
+			name: "TracesEventName",
+			extra: map[string]any{
+				"traces": map[string]any{"event_name": map[string]any{"attribute": "span.kind", "template": "{value}"}},
+			},
+		},
+		{
+			name:    "MissingEndpoint",
+			extra:   map[string]any{"endpoint": ""},
+			wantErr: `requires a non-empty "endpoint"`,
+		},
+		{
+			name:    "InvalidAuthMethod",
+			extra:   map[string]any{"auth_method": 5},
+			wantErr: "invalid auth_method: 5",
+		},
+		{
+			name:    "CertificateWithoutPath",
+			extra:   map[string]any{"auth_method": 1},
+			wantErr: `requires a non-empty "cert_path"`,
+		},
+		{
+			name:    "NegativeMaxRequestBytes",
+			extra:   map[string]any{"max_request_bytes": -1},
+			wantErr: "invalid max_request_bytes: -1",
+		},
+		{
+			name:    "InvalidEventName",
+			extra:   map[string]any{"event_name": map[string]any{"source": "scope"}},
+			wantErr: `invalid event_name: invalid source "scope"`,
+		},
+		{
+			name:    "EmptyBodyFlattenPrefix",
+			extra:   map[string]any{"body_mode": "flatten", "body_flatten": map[string]any{"prefix": ""}},
+			wantErr: "body_flatten::prefix must not be empty",
+		},
+		{
+			name:    "InvalidBodyFlattenPrefix",
+			extra:   map[string]any{"body_mode": "flatten", "body_flatten": map[string]any{"prefix": "body-"}},
+			wantErr: `invalid body_flatten::prefix "body-"`,
+		},
+		{
+			name: "InvalidMergedLogs",
+			extra: map[string]any{
+				"logs": map[string]any{"event_name": map[string]any{"template": "Logs"}},
+			},
+			wantErr: `logs: invalid event_name: template "Logs" must contain "{value}"`,
+		},
+		{
+			name: "InvalidMergedTraces",
+			extra: map[string]any{
+				"traces": map[string]any{"event_name": map[string]any{"default": "1Spans"}},
+			},
+			wantErr: `traces: invalid event_name: invalid default event name "1Spans"`,
+		},
+		{
+			name: "EmptyNamespaceOverrideIsInherited",
+			extra: map[string]any{
+				"logs": map[string]any{"namespace": ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := unmarshalTestConfig(t, newTestConfigMap(tt.extra))
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	defaultTraceIDColumn    = "TraceId"
+	defaultSpanIDColumn     = "SpanId"
+	defaultTraceFlagsColumn = "TraceFlags"
+
+	// defaultTraceEventName is the Geneva event the span encoder writes spans to.
+	defaultTraceEventName = "Span"
+)
+
+// CorrelationConfig configures the trace context columns that are added to exported log
+// records, so that warm-path logs can be joined to the spans exported by the traces exporter.
+type CorrelationConfig struct {
+	// Enabled turns the correlation columns on (default: false).
+	Enabled bool `mapstructure:"enabled"`
+	// TraceIDColumn receives the hex encoded trace ID (default: "TraceId").
+	TraceIDColumn string `mapstructure:"trace_id_column"`
+	// SpanIDColumn receives the hex encoded span ID (default: "SpanId").
+	SpanIDColumn string `mapstructure:"span_id_column"`
+	// TraceFlagsColumn receives the hex encoded trace flags, e.g. "01" (default: "TraceFlags").
+	TraceFlagsColumn string `mapstructure:"trace_flags_column"`
+	// TraceEventNameColumn receives TraceEventName on records with a trace ID. Disabled when empty.
+	TraceEventNameColumn string `mapstructure:"trace_event_name_column"`
+	// TraceEventName is the Geneva event that holds the correlated spans. When empty, the
+	// event the traces exporter routes spans to is used (see traceEvents).
+	TraceEventName string `mapstructure:"trace_event_name"`
+
+	// traceEvents is the event name routing of the traces exporter, set by logsConfig.
+	traceEvents EventNameConfig
+}
+
+// Validate checks if the correlation configuration is valid.
+func (c *CorrelationConfig) Validate() error {
+	columns := make(map[string]struct{})
+	for _, col := range []struct{ field, name string }{
+		{"trace_id_column", c.TraceIDColumn},
+		{"span_id_column", c.SpanIDColumn},
+		{"trace_flags_column", c.TraceFlagsColumn},
+		{"trace_event_name_column", c.TraceEventNameColumn},
+	} {
+		if col.name == "" {
+			continue
+		}
+		if !isValidGenevaName(col.name) {
+			return fmt.Errorf(`invalid %s %q`, col.field, col.name)
+		}
+		if _, ok := columns[col.name]; ok {
+			return fmt.Errorf(`column %q is used more than once`, col.name)
+		}
+		columns[col.name] = struct{}{}
+	}
+	if c.TraceEventName != "" && !isValidGenevaName(c.TraceEventName) {
+		return fmt.Errorf(`invalid trace_event_name %q`, c.TraceEventName)
+	}
+	if c.TraceEventName != "" && c.TraceEventNameColumn == "" {
+		return errors.New(`"trace_event_name" requires "trace_event_name_column"`)
+	}
+	return nil
+}
+
+// correlator writes trace context columns to log records.
+type correlator struct {
+	traceIDColumn        string
+	spanIDColumn         string
+	traceFlagsColumn     string
+	traceEventNameColumn string
+	traceEventName       string
+	traceRouter          *eventNameRouter
+}
+
+// newCorrelator creates a correlator for cfg, or returns nil if correlation is disabled.
+func newCorrelator(cfg CorrelationConfig) *correlator {
+	if !cfg.Enabled {
+		return nil
+	}
+	c := &correlator{
+		traceIDColumn:        cfg.TraceIDColumn,
+		spanIDColumn:         cfg.SpanIDColumn,
+		traceFlagsColumn:     cfg.TraceFlagsColumn,
+		traceEventNameColumn: cfg.TraceEventNameColumn,
+		traceEventName:       cfg.TraceEventName,
+	}
+	if c.traceIDColumn == "" {
+		c.traceIDColumn = defaultTraceIDColumn
+	}
+	if c.spanIDColumn == "" {
+		c.spanIDColumn = defaultSpanIDColumn
+	}
+	if c.traceFlagsColumn == "" {
+		c.traceFlagsColumn = defaultTraceFlagsColumn
+	}
+	if c.traceEventName == "" {
+		c.traceRouter = newEventNameRouter(cfg.traceEvents)
+	}
+	return c
+}
+
+// traceEventNameOf returns the Geneva event that holds the spans correlated with a log
+// record: the configured name, else the event the traces exporter routes the record's
+// attributes to, else the span encoder's fixed event.
+func (c *correlator) traceEventNameOf(resourceAttrs, recordAttrs pcommon.Map) string {
+	if c.traceEventName != "" {
+		return c.traceEventName
+	}
+	if c.traceRouter != nil {
+		if name := c.traceRouter.resolve(resourceAttrs, recordAttrs); name != "" {
+			return name
+		}
+	}
+	return defaultTraceEventName
+}
+
+// correlateLogs adds the trace context columns to every log record in ld that has a
+// trace ID. Columns the record already sets keep their value.
+func (c *correlator) correlateLogs(ld plog.Logs) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		resourceAttrs := rl.Resource().Attributes()
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				lr := records.At(k)
+				if lr.TraceID().IsEmpty() {
+					continue
+				}
+				attrs := lr.Attributes()
+				putIfAbsent := func(key, value string) {
+					if _, ok := attrs.Get(key); !ok {
+						attrs.PutStr(key, value)
+					}
+				}
+				traceID := lr.TraceID()
+				putIfAbsent(c.traceIDColumn, hex.EncodeToString(traceID[:]))
+				if !lr.SpanID().IsEmpty() {
+					spanID := lr.SpanID()
+					putIfAbsent(c.spanIDColumn, hex.EncodeToString(spanID[:]))
+				}
+				putIfAbsent(c.traceFlagsColumn, fmt.Sprintf("%02x", uint8(lr.Flags())))
+				if c.traceEventNameColumn != "" {
+					putIfAbsent(c.traceEventNameColumn, c.traceEventNameOf(resourceAttrs, attrs))
+				}
+			}
+		}
+	}
+}
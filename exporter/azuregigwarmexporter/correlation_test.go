@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+var (
+	testTraceID = pcommon.TraceID([16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36})
+	testSpanID  = pcommon.SpanID([8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7})
+)
+
+func TestCorrelateLogs(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   CorrelationConfig
+		setup func(lr plog.LogRecord)
+		want  map[string]any
+	}{
+		{
+			name: "Defaults",
+			cfg:  CorrelationConfig{Enabled: true},
+			setup: func(lr plog.LogRecord) {
+				lr.SetTraceID(testTraceID)
+				lr.SetSpanID(testSpanID)
+				lr.SetFlags(plog.DefaultLogRecordFlags.WithIsSampled(true))
+			},
+			want: map[string]any{
+				"TraceId":    "4bf92f3577b34da6a3ce929d0e0e4736",
+				"SpanId":     "00f067aa0ba902b7",
+				"TraceFlags": "01",
+			},
+		},
+		{
+			name: "CustomColumnsAndEventName",
+			cfg: CorrelationConfig{
+				Enabled:              true,
+				TraceIDColumn:        "trace",
+				SpanIDColumn:         "span",
+				TraceFlagsColumn:     "flags",
+				TraceEventNameColumn: "TraceEvent",
+			},
+			setup: func(lr plog.LogRecord) {
+				lr.SetTraceID(testTraceID)
+				lr.SetSpanID(testSpanID)
+			},
+			want: map[string]any{
+				"trace":      "4bf92f3577b34da6a3ce929d0e0e4736",
+				"span":       "00f067aa0ba902b7",
+				"flags":      "00",
+				"TraceEvent": "Span",
+			},
+		},
+		{
+			name: "RoutedTraceEvent",
+			cfg: CorrelationConfig{
+				Enabled:              true,
+				TraceEventNameColumn: "TraceEvent",
+				traceEvents:          EventNameConfig{Attribute: "service.name", Template: "Spans_{value}"},
+			},
+			setup: func(lr plog.LogRecord) {
+				lr.SetTraceID(testTraceID)
+				lr.Attributes().PutStr("service.name", "checkout")
+			},
+			want: map[string]any{
+				"TraceId":      "4bf92f3577b34da6a3ce929d0e0e4736",
+				"TraceFlags":   "00",
+				"TraceEvent":   "Spans_checkout",
+				"service.name": "checkout",
+			},
+		},
+		{
+			name: "TraceEventDefault",
+			cfg: CorrelationConfig{
+				Enabled:              true,
+				TraceEventNameColumn: "TraceEvent",
+				traceEvents:          EventNameConfig{Attribute: "service.name", Default: "AppSpans"},
+			},
+			setup: func(lr plog.LogRecord) { lr.SetTraceID(testTraceID) },
+			want: map[string]any{
+				"TraceId":    "4bf92f3577b34da6a3ce929d0e0e4736",
+				"TraceFlags": "00",
+				"TraceEvent": "AppSpans",
+			},
+		},
+		{
+			name: "ConfiguredTraceEventName",
+			cfg: CorrelationConfig{
+				Enabled:              true,
+				TraceEventNameColumn: "TraceEvent",
+				TraceEventName:       "Requests",
+				traceEvents:          EventNameConfig{Default: "AppSpans"},
+			},
+			setup: func(lr plog.LogRecord) { lr.SetTraceID(testTraceID) },
+			want: map[string]any{
+				"TraceId":    "4bf92f3577b34da6a3ce929d0e0e4736",
+				"TraceFlags": "00",
+				"TraceEvent": "Requests",
+			},
+		},
+		{
+			name:  "WithoutSpanID",
+			cfg:   CorrelationConfig{Enabled: true},
+			setup: func(lr plog.LogRecord) { lr.SetTraceID(testTraceID) },
+			want: map[string]any{
+				"TraceId":    "4bf92f3577b34da6a3ce929d0e0e4736",
+				"TraceFlags": "00",
+			},
+		},
+		{
+			name: "ExistingColumnsKept",
+			cfg:  CorrelationConfig{Enabled: true},
+			setup: func(lr plog.LogRecord) {
+				lr.SetTraceID(testTraceID)
+				lr.Attributes().PutStr("TraceId", "custom")
+			},
+			want: map[string]any{
+				"TraceId":    "custom",
+				"TraceFlags": "00",
+			},
+		},
+		{
+			name:  "WithoutTraceID",
+			cfg:   CorrelationConfig{Enabled: true, TraceEventNameColumn: "TraceEvent"},
+			setup: func(lr plog.LogRecord) { lr.SetSpanID(testSpanID) },
+			want:  map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.cfg.Validate())
+			c := newCorrelator(tt.cfg)
+			require.NotNil(t, c)
+
+			ld := plog.NewLogs()
+			lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+			tt.setup(lr)
+			c.correlateLogs(ld)
+			assert.Equal(t, tt.want, lr.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestCorrelationConfigValidate(t *testing.T) {
+	assert.Nil(t, newCorrelator(CorrelationConfig{}))
+
+	tests := []struct {
+		name    string
+		cfg     CorrelationConfig
+		wantErr string
+	}{
+		{name: "InvalidColumn", cfg: CorrelationConfig{SpanIDColumn: "span-id"}, wantErr: `invalid span_id_column "span-id"`},
+		{name: "DuplicateColumn", cfg: CorrelationConfig{TraceIDColumn: "Id", SpanIDColumn: "Id"}, wantErr: `column "Id" is used more than once`},
+		{name: "EventNameWithoutColumn", cfg: CorrelationConfig{TraceEventName: "Spans"}, wantErr: `requires "trace_event_name_column"`},
+		{name: "InvalidEventName", cfg: CorrelationConfig{TraceEventNameColumn: "Event", TraceEventName: "1Span"}, wantErr: `invalid trace_event_name "1Span"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.cfg.Validate(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	defaultDebugDumpMaxFiles = 100
+	defaultDebugDumpMaxBytes = 64 << 20
+
+	debugDumpPayloadExt = ".bin"
+	debugDumpSidecarExt = ".json"
+
+	// defaultLogEventName is the event the Rust encoder uses for records without an event name.
+	defaultLogEventName = "Log"
+)
+
+// DebugDumpConfig configures writing encoded batches to local files for inspection.
+type DebugDumpConfig struct {
+	// Dir is the directory batches are written to. Dumping is disabled when empty.
+	Dir string `mapstructure:"debug_dump_dir"`
+	// MaxFiles is the maximum number of dumped batches kept in Dir (default: 100).
+	MaxFiles int `mapstructure:"debug_dump_max_files"`
+	// MaxBytes is the maximum total size of the dumped payloads kept in Dir (default: 64 MiB).
+	MaxBytes int64 `mapstructure:"debug_dump_max_bytes"`
+}
+
+// Validate checks if the debug dump configuration is valid.
+func (c *DebugDumpConfig) Validate() error {
+	if c.MaxFiles < 0 {
+		return fmt.Errorf("invalid debug_dump_max_files: %d (must be >= 0)", c.MaxFiles)
+	}
+	if c.MaxBytes < 0 {
+		return fmt.Errorf("invalid debug_dump_max_bytes: %d (must be >= 0)", c.MaxBytes)
+	}
+	if c.Dir == "" && (c.MaxFiles != 0 || c.MaxBytes != 0) {
+		return errors.New(`"debug_dump_max_files" and "debug_dump_max_bytes" require "debug_dump_dir"`)
+	}
+	return nil
+}
+
+// dumpSidecar is the JSON document written next to every dumped payload.
+type dumpSidecar struct {
+	Signal       string     `json:"signal"`
+	EventName    string     `json:"event_name"`
+	RowCount     *int       `json:"row_count,omitempty"`
+	Schema       dumpSchema `json:"schema"`
+	StartTime    uint64     `json:"start_time_unix_nano"`
+	EndTime      uint64     `json:"end_time_unix_nano"`
+	PayloadFile  string     `json:"payload_file"`
+	PayloadBytes int        `json:"payload_bytes"`
+}
+
+// dumpSchema describes the rows of a dumped batch.
+type dumpSchema struct {
+	// IDs are the schema IDs reported by the encoder.
+	IDs string `json:"ids"`
+	// Attributes are the attribute keys (dynamic columns) of the rows of the event.
+	Attributes []string `json:"attributes"`
+}
+
+// dumpedBatch is one encoded batch handed to the dumper.
+type dumpedBatch struct {
+	eventName string
+	schemaIDs string
+	startTime uint64
+	endTime   uint64
+	data      []byte
+}
+
+// eventShape summarizes the rows of one Geneva event in a request.
+type eventShape struct {
+	rows       int
+	attributes map[string]struct{}
+}
+
+// batchDumper writes encoded batches to a directory and removes the oldest dumps once
+// the file count or payload size limits are exceeded.
+type batchDumper struct {
+	dir      string
+	maxFiles int
+	maxBytes int64
+
+	mu    sync.Mutex
+	seq   uint64
+	files []dumpedFile
+	bytes int64
+
+	// refs counts the exporters sharing the dumper; guarded by batchDumpers.mu
+	refs int
+}
+
+// batchDumpers holds one dumper per directory. The logs and traces exporters of a
+// configuration dump to the same directory, and separate dumpers would each rotate
+// only their own files and race on the sequence numbers.
+var batchDumpers = struct {
+	mu    sync.Mutex
+	byDir map[string]*batchDumper
+}{byDir: make(map[string]*batchDumper)}
+
+// dumpedFile is a payload and sidecar pair on disk.
+type dumpedFile struct {
+	base string
+	size int64
+}
+
+// acquireBatchDumper returns the dumper of cfg.Dir, creating it on first use, or returns
+// nil if dumping is disabled. The limits of the first configuration that acquires a
+// directory apply. Every acquired dumper must be released.
+func acquireBatchDumper(cfg DebugDumpConfig) (*batchDumper, error) {
+	if cfg.Dir == "" {
+		return nil, nil
+	}
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid debug dump directory: %w", err)
+	}
+
+	batchDumpers.mu.Lock()
+	defer batchDumpers.mu.Unlock()
+	d, ok := batchDumpers.byDir[dir]
+	if !ok {
+		cfg.Dir = dir
+		if d, err = newBatchDumper(cfg); err != nil {
+			return nil, err
+		}
+		batchDumpers.byDir[dir] = d
+	}
+	d.refs++
+	return d, nil
+}
+
+// release drops a reference acquired by acquireBatchDumper. The dumper is forgotten once
+// the last exporter using it has released it, so that a restarted pipeline indexes the
+// directory again.
+func (d *batchDumper) release() {
+	if d == nil {
+		return
+	}
+	batchDumpers.mu.Lock()
+	defer batchDumpers.mu.Unlock()
+	d.refs--
+	if d.refs <= 0 && batchDumpers.byDir[d.dir] == d {
+		delete(batchDumpers.byDir, d.dir)
+	}
+}
+
+// newBatchDumper creates the dump directory and indexes dumps left by a previous run.
+func newBatchDumper(cfg DebugDumpConfig) (*batchDumper, error) {
+	d := &batchDumper{dir: cfg.Dir, maxFiles: cfg.MaxFiles, maxBytes: cfg.MaxBytes}
+	if d.maxFiles == 0 {
+		d.maxFiles = defaultDebugDumpMaxFiles
+	}
+	if d.maxBytes == 0 {
+		d.maxBytes = defaultDebugDumpMaxBytes
+	}
+	if err := os.MkdirAll(d.dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create debug dump directory: %w", err)
+	}
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read debug dump directory: %w", err)
+	}
+	// Names start with a zero-padded timestamp, so ReadDir order is oldest first
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, debugDumpPayloadExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		d.files = append(d.files, dumpedFile{base: strings.TrimSuffix(name, debugDumpPayloadExt), size: info.Size()})
+		d.bytes += info.Size()
+	}
+	d.mu.Lock()
+	d.rotateLocked()
+	d.mu.Unlock()
+	return d, nil
+}
+
+// dump writes the batches of one encoder call. shapes describes the rows per event name;
+// the row count is written when an event was encoded into a single batch.
+func (d *batchDumper) dump(signal string, batches []dumpedBatch, shapes map[string]*eventShape) error {
+	perEvent := make(map[string]int, len(batches))
+	for _, b := range batches {
+		perEvent[b.eventName]++
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var errs []error
+	for _, b := range batches {
+		d.seq++
+		base := fmt.Sprintf("%019d-%06d-%s-%s", time.Now().UnixNano(), d.seq%1000000, signal, dumpFileSafe(b.eventName))
+
+		sidecar := dumpSidecar{
+			Signal:       signal,
+			EventName:    b.eventName,
+			Schema:       dumpSchema{IDs: b.schemaIDs, Attributes: []string{}},
+			StartTime:    b.startTime,
+			EndTime:      b.endTime,
+			PayloadFile:  base + debugDumpPayloadExt,
+			PayloadBytes: len(b.data),
+		}
+		if shape, ok := shapes[b.eventName]; ok {
+			if perEvent[b.eventName] == 1 {
+				rows := shape.rows
+				sidecar.RowCount = &rows
+			}
+			for k := range shape.attributes {
+				sidecar.Schema.Attributes = append(sidecar.Schema.Attributes, k)
+			}
+			sort.Strings(sidecar.Schema.Attributes)
+		}
+		meta, err := json.MarshalIndent(sidecar, "", "  ")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := os.WriteFile(filepath.Join(d.dir, base+debugDumpPayloadExt), b.data, 0o640); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(d.dir, base+debugDumpSidecarExt), meta, 0o640); err != nil {
+			_ = os.Remove(filepath.Join(d.dir, base+debugDumpPayloadExt))
+			errs = append(errs, err)
+			continue
+		}
+		d.files = append(d.files, dumpedFile{base: base, size: int64(len(b.data))})
+		d.bytes += int64(len(b.data))
+		d.rotateLocked()
+	}
+	return errors.Join(errs...)
+}
+
+// rotateLocked removes the oldest dumps until both limits hold. The newest dump is always kept.
+func (d *batchDumper) rotateLocked() {
+	for len(d.files) > 1 && (len(d.files) > d.maxFiles || d.bytes > d.maxBytes) {
+		oldest := d.files[0]
+		_ = os.Remove(filepath.Join(d.dir, oldest.base+debugDumpPayloadExt))
+		_ = os.Remove(filepath.Join(d.dir, oldest.base+debugDumpSidecarExt))
+		d.files = d.files[1:]
+		d.bytes -= oldest.size
+	}
+}
+
+// dumpFileSafe replaces characters that are not safe in file names.
+func dumpFileSafe(s string) string {
+	if s == "" {
+		return "unnamed"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// addShape records a row and its attribute keys under eventName.
+func addShape(shapes map[string]*eventShape, eventName string, attrs pcommon.Map) {
+	shape, ok := shapes[eventName]
+	if !ok {
+		shape = &eventShape{attributes: make(map[string]struct{})}
+		shapes[eventName] = shape
+	}
+	shape.rows++
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		shape.attributes[k] = struct{}{}
+		return true
+	})
+}
+
+// logShapes returns the rows per Geneva event of ld.
+func logShapes(ld plog.Logs) map[string]*eventShape {
+	shapes := make(map[string]*eventShape)
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				lr := records.At(k)
+				name := lr.EventName()
+				if name == "" {
+					name = defaultLogEventName
+				}
+				addShape(shapes, name, lr.Attributes())
+			}
+		}
+	}
+	return shapes
+}
+
+// spanShapes returns the rows of td, whose spans are all written to eventName, or to the
+// encoder's fixed Span event when it is empty.
+func spanShapes(td ptrace.Traces, eventName string) map[string]*eventShape {
+	if eventName == "" {
+		eventName = defaultTraceEventName
+	}
+	shapes := make(map[string]*eventShape)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				addShape(shapes, eventName, spans.At(k).Attributes())
+			}
+		}
+	}
+	return shapes
+}
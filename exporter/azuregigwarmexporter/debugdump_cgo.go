@@ -0,0 +1,27 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build cgo
+
+package azuregigwarmexporter
+
+// dumpEncodedBatches copies the payload and metadata of every batch out of the FFI handle
+// and writes them to the dump directory.
+func (d *batchDumper) dumpEncodedBatches(signal string, batches encodedBatches, shapes map[string]*eventShape) error {
+	n := batches.Len()
+	dumped := make([]dumpedBatch, 0, n)
+	for i := range n {
+		info, err := batches.Batch(i)
+		if err != nil {
+			return err
+		}
+		dumped = append(dumped, dumpedBatch{
+			eventName: info.EventName,
+			schemaIDs: info.SchemaIDs,
+			startTime: info.StartTime,
+			endTime:   info.EndTime,
+			data:      info.Data,
+		})
+	}
+	return d.dump(signal, dumped, shapes)
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// dumpedPayloads returns the names of the payload files in dir.
+func dumpedPayloads(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), debugDumpPayloadExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+func TestBatchDumperRotation(t *testing.T) {
+	dir := t.TempDir()
+	d, err := acquireBatchDumper(DebugDumpConfig{Dir: dir, MaxFiles: 2, MaxBytes: 10})
+	require.NoError(t, err)
+	defer d.release()
+
+	require.NoError(t, d.dump("logs", []dumpedBatch{{eventName: "Log", data: []byte("aaaa")}}, nil))
+	require.NoError(t, d.dump("logs", []dumpedBatch{{eventName: "Log", data: []byte("bbbb")}}, nil))
+	require.NoError(t, d.dump("logs", []dumpedBatch{{eventName: "Log", data: []byte("cc")}}, nil))
+	names := dumpedPayloads(t, dir)
+	require.Len(t, names, 2, "max_files")
+	assert.Contains(t, names[0], "-logs-Log")
+
+	require.NoError(t, d.dump("traces", []dumpedBatch{{eventName: "Span", data: []byte("dddddddddddd")}}, nil))
+	names = dumpedPayloads(t, dir)
+	require.Len(t, names, 1, "the newest dump is kept even if it exceeds max_bytes")
+	assert.Contains(t, names[0], "-traces-Span")
+}
+
+func TestBatchDumperSidecar(t *testing.T) {
+	dir := t.TempDir()
+	d, err := acquireBatchDumper(DebugDumpConfig{Dir: dir})
+	require.NoError(t, err)
+	defer d.release()
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().Attributes().PutStr("b", "x")
+	lr := records.AppendEmpty()
+	lr.SetEventName("Audit")
+	lr.Attributes().PutStr("a", "x")
+	records.AppendEmpty().Attributes().PutStr("a", "x")
+
+	require.NoError(t, d.dump("logs", []dumpedBatch{
+		{eventName: "Log", schemaIDs: "1;2", startTime: 1, endTime: 2, data: []byte("payload")},
+	}, logShapes(ld)))
+
+	names := dumpedPayloads(t, dir)
+	require.Len(t, names, 1)
+	payload, err := os.ReadFile(filepath.Join(dir, names[0]))
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(payload))
+
+	raw, err := os.ReadFile(filepath.Join(dir, strings.TrimSuffix(names[0], debugDumpPayloadExt)+debugDumpSidecarExt))
+	require.NoError(t, err)
+	var sidecar dumpSidecar
+	require.NoError(t, json.Unmarshal(raw, &sidecar))
+	require.NotNil(t, sidecar.RowCount)
+	assert.Equal(t, 2, *sidecar.RowCount)
+	assert.Equal(t, dumpSchema{IDs: "1;2", Attributes: []string{"a", "b"}}, sidecar.Schema)
+	assert.Equal(t, names[0], sidecar.PayloadFile)
+	assert.Equal(t, len("payload"), sidecar.PayloadBytes)
+}
+
+func TestBatchDumperSharedPerDirectory(t *testing.T) {
+	dir := t.TempDir()
+	logs, err := acquireBatchDumper(DebugDumpConfig{Dir: dir, MaxFiles: 2})
+	require.NoError(t, err)
+	traces, err := acquireBatchDumper(DebugDumpConfig{Dir: dir + "/.", MaxFiles: 50})
+	require.NoError(t, err)
+	assert.Same(t, logs, traces, "the logs and traces exporters share the dumper of a directory")
+
+	// Dumps of both signals are rotated together
+	for range 2 {
+		require.NoError(t, logs.dump("logs", []dumpedBatch{{eventName: "Log", data: []byte("l")}}, nil))
+		require.NoError(t, traces.dump("traces", []dumpedBatch{{eventName: "Span", data: []byte("t")}}, nil))
+	}
+	assert.Len(t, dumpedPayloads(t, dir), 2)
+
+	logs.release()
+	again, err := acquireBatchDumper(DebugDumpConfig{Dir: dir})
+	require.NoError(t, err)
+	assert.Same(t, traces, again, "the dumper is kept while it is in use")
+	again.release()
+	traces.release()
+
+	// A dumper created after the last release indexes the dumps left in the directory
+	fresh, err := acquireBatchDumper(DebugDumpConfig{Dir: dir, MaxFiles: 1})
+	require.NoError(t, err)
+	defer fresh.release()
+	assert.NotSame(t, traces, fresh)
+	assert.Len(t, dumpedPayloads(t, dir), 1)
+}
+
+func TestDebugDumpConfigValidate(t *testing.T) {
+	d, err := acquireBatchDumper(DebugDumpConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, d)
+	d.release()
+
+	assert.NoError(t, (&DebugDumpConfig{Dir: "dump", MaxFiles: 1, MaxBytes: 1}).Validate())
+	assert.ErrorContains(t, (&DebugDumpConfig{Dir: "dump", MaxFiles: -1}).Validate(), "invalid debug_dump_max_files")
+	assert.ErrorContains(t, (&DebugDumpConfig{Dir: "dump", MaxBytes: -1}).Validate(), "invalid debug_dump_max_bytes")
+	assert.ErrorContains(t, (&DebugDumpConfig{MaxFiles: 1}).Validate(), `require "debug_dump_dir"`)
+}
+
+func TestSpanShapes(t *testing.T) {
+	td, span := newSpanEventsTestTraces()
+	span.Attributes().PutStr("http.route", "/cart")
+
+	shapes := spanShapes(td, "Spans_checkout")
+	require.Len(t, shapes, 1)
+	shape := shapes["Spans_checkout"]
+	require.NotNil(t, shape, "routed spans are keyed under their event")
+	assert.Equal(t, 1, shape.rows)
+	assert.Equal(t, map[string]struct{}{"exception.type": {}, "http.route": {}}, shape.attributes)
+
+	assert.Contains(t, spanShapes(td, ""), defaultTraceEventName)
+}
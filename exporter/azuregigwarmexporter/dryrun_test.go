@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build cgo
+
+package azuregigwarmexporter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	cgogeneva "github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter/internal/cgo"
+)
+
+// fakeBatches holds two batches of the event they were encoded for.
+type fakeBatches struct {
+	client    *fakeClient
+	eventName string
+}
+
+func (b *fakeBatches) Len() int { return 2 }
+
+func (b *fakeBatches) Batch(idx int) (cgogeneva.BatchInfo, error) {
+	if idx < 0 || idx >= b.Len() {
+		return cgogeneva.BatchInfo{}, errors.New("index out of range")
+	}
+	return cgogeneva.BatchInfo{EventName: b.eventName, SchemaIDs: "1", Data: []byte("payload")}, nil
+}
+
+func (b *fakeBatches) Size() (int, error) { return b.Len() * len("payload"), nil }
+
+func (b *fakeBatches) Close() {
+	b.client.mu.Lock()
+	defer b.client.mu.Unlock()
+	b.client.closed++
+}
+
+// fakeClient is a genevaClient that records encoder and upload calls.
+type fakeClient struct {
+	mu      sync.Mutex
+	encoded []string
+	uploads int
+	closed  int
+}
+
+func (c *fakeClient) encode(eventName string) (encodedBatches, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.encoded = append(c.encoded, eventName)
+	return &fakeBatches{client: c, eventName: eventName}, nil
+}
+
+func (c *fakeClient) EncodeAndCompressLogs([]byte) (encodedBatches, error) {
+	return c.encode("Log")
+}
+
+func (c *fakeClient) EncodeAndCompressSpans([]byte) (encodedBatches, error) {
+	return c.encode("Span")
+}
+
+func (c *fakeClient) EncodeAndCompressSpansAs(_ []byte, eventName string) (encodedBatches, error) {
+	return c.encode(eventName)
+}
+
+func (c *fakeClient) UploadBatch(encodedBatches, int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.uploads++
+	return nil
+}
+
+func (*fakeClient) Shutdown(context.Context) error { return nil }
+
+// newDryRunTestConfig returns a dry-run configuration that dumps batches to a temporary
+// directory, and a logger that records the exporter's log entries.
+func newDryRunTestConfig(t *testing.T) (*Config, *zap.Logger, *observer.ObservedLogs) {
+	cfg := newBenchConfig("http://127.0.0.1:1")
+	cfg.DebugDump.Dir = t.TempDir()
+	core, logs := observer.New(zapcore.InfoLevel)
+	return cfg, zap.New(core), logs
+}
+
+// dryRunSummary returns the fields of the single dry-run summary entry with message msg.
+func dryRunSummary(t *testing.T, logs *observer.ObservedLogs, msg string) map[string]any {
+	entries := logs.FilterMessage(msg).All()
+	require.Len(t, entries, 1)
+	return entries[0].ContextMap()
+}
+
+// countDumped returns the number of dumped payloads of signal in dir.
+func countDumped(t *testing.T, dir, signal string) int {
+	n := 0
+	for _, name := range dumpedPayloads(t, dir) {
+		if strings.Contains(name, "-"+signal+"-") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLogsDryRun(t *testing.T) {
+	cfg, logger, logs := newDryRunTestConfig(t)
+	set := newPolicyTestSettings()
+	tel, err := newTelemetry(set.TelemetrySettings)
+	require.NoError(t, err)
+	dumper, err := acquireBatchDumper(cfg.DebugDump)
+	require.NoError(t, err)
+	client := &fakeClient{}
+	e := &logsExporter{params: set, cfg: cfg, client: client, logger: logger, telemetry: tel, dumper: dumper}
+	defer func() { require.NoError(t, e.shutdown(context.Background())) }()
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for range 3 {
+		records.AppendEmpty().Body().SetStr("checkout failed")
+	}
+	require.NoError(t, e.pushLogs(context.Background(), ld))
+
+	assert.Equal(t, []string{"Log"}, client.encoded)
+	assert.Zero(t, client.uploads, "dry run never uploads")
+	assert.Equal(t, 1, client.closed)
+	assert.Equal(t, map[string]any{
+		"log_records": int64(3),
+		"chunks":      int64(1),
+		"batches":     int64(2),
+		"bytes":       int64(2 * len("payload")),
+	}, dryRunSummary(t, logs, "Dry run: encoded logs without uploading"))
+	assert.Equal(t, 2, countDumped(t, cfg.DebugDump.Dir, "logs"))
+}
+
+func TestTracesDryRun(t *testing.T) {
+	cfg, logger, logs := newDryRunTestConfig(t)
+	cfg.SpanEvents = SpanEventsConfig{Mode: SpanEventsModeRows}
+	set := newPolicyTestSettings()
+	tel, err := newTelemetry(set.TelemetrySettings)
+	require.NoError(t, err)
+	dumper, err := acquireBatchDumper(cfg.DebugDump)
+	require.NoError(t, err)
+	client := &fakeClient{}
+	e := &tracesExporter{
+		params:    set,
+		cfg:       cfg,
+		client:    client,
+		logger:    logger,
+		telemetry: tel,
+		spans:     newSpanTransformer(cfg.SpanEvents, cfg.SpanLinks),
+		dumper:    dumper,
+	}
+	defer func() { require.NoError(t, e.shutdown(context.Background())) }()
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for range 2 {
+		span := spans.AppendEmpty()
+		span.SetName("GET /cart")
+		span.Events().AppendEmpty().SetTimestamp(pcommon.Timestamp(1000))
+	}
+	require.NoError(t, e.pushTraces(context.Background(), td))
+
+	assert.Equal(t, []string{"Log", "Span"}, client.encoded, "span events are encoded as rows, then the spans")
+	assert.Zero(t, client.uploads, "dry run never uploads")
+	assert.Equal(t, 2, client.closed)
+	assert.Equal(t, map[string]any{
+		"event_count": int64(2),
+		"chunks":      int64(1),
+		"batches":     int64(2),
+		"bytes":       int64(2 * len("payload")),
+	}, dryRunSummary(t, logs, "Dry run: encoded span events without uploading"))
+	assert.Equal(t, map[string]any{
+		"span_count": int64(2),
+		"chunks":     int64(1),
+		"batches":    int64(2),
+		"bytes":      int64(2 * len("payload")),
+	}, dryRunSummary(t, logs, "Dry run: encoded spans without uploading"))
+	assert.Equal(t, 2, countDumped(t, cfg.DebugDump.Dir, "span_events"))
+	assert.Equal(t, 2, countDumped(t, cfg.DebugDump.Dir, "traces"))
+}
@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// EnrichmentSourceRoleName resolves to the configured (or GENEVA_ROLE_NAME) role name.
+	EnrichmentSourceRoleName = "role_name"
+	// EnrichmentSourceRoleInstance resolves to the configured (or GENEVA_ROLE_INSTANCE) role instance.
+	EnrichmentSourceRoleInstance = "role_instance"
+	// EnrichmentSourceHostName resolves to the host name reported by the operating system.
+	EnrichmentSourceHostName = "host_name"
+	// enrichmentSourceEnvPrefix marks a source that is read from an environment variable,
+	// e.g. "env:K8S_POD_NAME".
+	enrichmentSourceEnvPrefix = "env:"
+)
+
+// defaultEnrichmentAttributes is used when enrichment is enabled without explicit attributes.
+// The K8S_* variables are expected to be set from the Kubernetes downward API.
+var defaultEnrichmentAttributes = map[string]string{
+	"cloud.role":          EnrichmentSourceRoleName,
+	"cloud.role.instance": EnrichmentSourceRoleInstance,
+	"host.name":           EnrichmentSourceHostName,
+	"k8s.pod.name":        enrichmentSourceEnvPrefix + "K8S_POD_NAME",
+	"k8s.pod.uid":         enrichmentSourceEnvPrefix + "K8S_POD_UID",
+	"k8s.namespace.name":  enrichmentSourceEnvPrefix + "K8S_NAMESPACE_NAME",
+	"k8s.node.name":       enrichmentSourceEnvPrefix + "K8S_NODE_NAME",
+	"container.name":      enrichmentSourceEnvPrefix + "K8S_CONTAINER_NAME",
+}
+
+// EnrichmentConfig configures the host and Geneva role metadata that is added to every
+// log record and span before encoding.
+type EnrichmentConfig struct {
+	// Enabled turns enrichment on (default: false).
+	Enabled bool `mapstructure:"enabled"`
+	// Attributes maps attribute keys to value sources: "role_name", "role_instance",
+	// "host_name" or "env:<VARIABLE>". When empty, a default set covering role, host,
+	// container and Kubernetes identifiers is used.
+	Attributes map[string]string `mapstructure:"attributes"`
+}
+
+// Validate checks if the enrichment configuration is valid.
+func (c *EnrichmentConfig) Validate() error {
+	for key, source := range c.Attributes {
+		if key == "" {
+			return fmt.Errorf("attributes: empty attribute key for source %q", source)
+		}
+		switch {
+		case source == EnrichmentSourceRoleName, source == EnrichmentSourceRoleInstance, source == EnrichmentSourceHostName:
+		case strings.HasPrefix(source, enrichmentSourceEnvPrefix) && len(source) > len(enrichmentSourceEnvPrefix):
+		default:
+			return fmt.Errorf(`attributes[%q]: invalid source %q (must be %q, %q, %q or "env:<VARIABLE>")`, key, source,
+				EnrichmentSourceRoleName, EnrichmentSourceRoleInstance, EnrichmentSourceHostName)
+		}
+	}
+	return nil
+}
+
+// enrichmentAttribute is a resolved attribute added by the enricher.
+type enrichmentAttribute struct {
+	key   string
+	value string
+}
+
+// enricher adds resolved metadata attributes to records that do not set them yet.
+type enricher struct {
+	attrs []enrichmentAttribute
+}
+
+// newEnricher resolves the configured sources once, or returns nil if enrichment is
+// disabled or no source resolves to a value.
+func newEnricher(cfg EnrichmentConfig, roleName, roleInstance string) *enricher {
+	if !cfg.Enabled {
+		return nil
+	}
+	sources := cfg.Attributes
+	if len(sources) == 0 {
+		sources = defaultEnrichmentAttributes
+	}
+
+	e := &enricher{}
+	for key, source := range sources {
+		var value string
+		switch {
+		case source == EnrichmentSourceRoleName:
+			value = roleName
+		case source == EnrichmentSourceRoleInstance:
+			value = roleInstance
+		case source == EnrichmentSourceHostName:
+			value, _ = os.Hostname()
+		case strings.HasPrefix(source, enrichmentSourceEnvPrefix):
+			value = os.Getenv(strings.TrimPrefix(source, enrichmentSourceEnvPrefix))
+		}
+		if value != "" {
+			e.attrs = append(e.attrs, enrichmentAttribute{key: key, value: value})
+		}
+	}
+	if len(e.attrs) == 0 {
+		return nil
+	}
+	sort.Slice(e.attrs, func(i, j int) bool { return e.attrs[i].key < e.attrs[j].key })
+	return e
+}
+
+// enrich adds the resolved attributes to attrs. Attributes already set on the record or
+// its resource keep their value.
+func (e *enricher) enrich(resourceAttrs, attrs pcommon.Map) {
+	for _, a := range e.attrs {
+		if _, ok := attrs.Get(a.key); ok {
+			continue
+		}
+		if _, ok := resourceAttrs.Get(a.key); ok {
+			continue
+		}
+		attrs.PutStr(a.key, a.value)
+	}
+}
+
+// enrichLogs adds the resolved attributes to every log record in ld.
+func (e *enricher) enrichLogs(ld plog.Logs) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		resourceAttrs := rl.Resource().Attributes()
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				e.enrich(resourceAttrs, records.At(k).Attributes())
+			}
+		}
+	}
+}
+
+// enrichTraces adds the resolved attributes to every span in td.
+func (e *enricher) enrichTraces(td ptrace.Traces) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		resourceAttrs := rs.Resource().Attributes()
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				e.enrich(resourceAttrs, spans.At(k).Attributes())
+			}
+		}
+	}
+}
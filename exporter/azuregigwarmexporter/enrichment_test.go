@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestNewEnricher(t *testing.T) {
+	t.Setenv("GIGWARM_TEST_ZONE", "zone-1")
+	t.Setenv("GIGWARM_TEST_EMPTY", "")
+
+	assert.Nil(t, newEnricher(EnrichmentConfig{}, "role", "instance"), "disabled")
+	assert.Nil(t, newEnricher(EnrichmentConfig{
+		Enabled:    true,
+		Attributes: map[string]string{"zone": "env:GIGWARM_TEST_EMPTY"},
+	}, "", ""), "no source resolves")
+
+	e := newEnricher(EnrichmentConfig{
+		Enabled: true,
+		Attributes: map[string]string{
+			"zone":  "env:GIGWARM_TEST_ZONE",
+			"role":  EnrichmentSourceRoleName,
+			"inst":  EnrichmentSourceRoleInstance,
+			"empty": "env:GIGWARM_TEST_EMPTY",
+		},
+	}, "checkout", "")
+	require.NotNil(t, e)
+	assert.Equal(t, []enrichmentAttribute{{key: "role", value: "checkout"}, {key: "zone", value: "zone-1"}}, e.attrs,
+		"unresolved sources are skipped and attributes are sorted by key")
+}
+
+func TestEnricherPrecedence(t *testing.T) {
+	e := &enricher{attrs: []enrichmentAttribute{
+		{key: "cloud.role", value: "from-config"},
+		{key: "host.name", value: "from-config"},
+		{key: "zone", value: "from-config"},
+	}}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("host.name", "from-resource")
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.Attributes().PutStr("cloud.role", "from-record")
+
+	e.enrichLogs(ld)
+	assert.Equal(t, map[string]any{
+		"cloud.role": "from-record",
+		"zone":       "from-config",
+	}, lr.Attributes().AsRaw(), "record and resource attributes take precedence")
+	assert.Equal(t, map[string]any{"host.name": "from-resource"}, rl.Resource().Attributes().AsRaw(),
+		"the resource is not modified")
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("zone", "from-resource")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+
+	e.enrichTraces(td)
+	assert.Equal(t, map[string]any{
+		"cloud.role": "from-config",
+		"host.name":  "from-config",
+	}, span.Attributes().AsRaw())
+}
+
+func TestEnrichmentConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		attrs   map[string]string
+		wantErr string
+	}{
+		{name: "Valid", attrs: map[string]string{"a": EnrichmentSourceHostName, "b": "env:ZONE"}},
+		{name: "EmptyKey", attrs: map[string]string{"": EnrichmentSourceHostName}, wantErr: "empty attribute key"},
+		{name: "EmptyEnvVariable", attrs: map[string]string{"a": "env:"}, wantErr: `invalid source "env:"`},
+		{name: "UnknownSource", attrs: map[string]string{"a": "hostname"}, wantErr: `invalid source "hostname"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := EnrichmentConfig{Enabled: true, Attributes: tt.attrs}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
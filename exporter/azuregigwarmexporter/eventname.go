@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// EventNameSourceRecord looks the routing attribute up on the log record (or span) only.
+	EventNameSourceRecord = "record"
+	// EventNameSourceResource looks the routing attribute up on the resource only.
+	EventNameSourceResource = "resource"
+	// EventNameSourceRecordThenResource looks the routing attribute up on the log record
+	// (or span) first and falls back to the resource.
+	EventNameSourceRecordThenResource = "record_then_resource"
+
+	// eventNameValuePlaceholder is replaced by the attribute value in EventNameConfig.Template.
+	eventNameValuePlaceholder = "{value}"
+
+	// maxGenevaNameLength is the maximum length of a Geneva event or column name.
+	maxGenevaNameLength = 100
+)
+
+// EventNameConfig configures routing of log records and spans to Geneva events (tables)
+// based on a record, span or resource attribute. The resolved name is written to the OTLP
+// event_name field of each log record, which the Rust encoder uses to group records into
+// events. Spans are grouped by their resolved name and each group is encoded to its event.
+type EventNameConfig struct {
+	// Attribute is the attribute key whose value selects the event name, e.g. "event.name"
+	// or "service.name". Routing is disabled when empty.
+	Attribute string `mapstructure:"attribute"`
+	// Source selects where Attribute is looked up: "record", "resource" or
+	// "record_then_resource" (default: "record_then_resource").
+	Source string `mapstructure:"source"`
+	// Template builds the event name from the attribute value; "{value}" is replaced by the
+	// value, e.g. "AppLogs_{value}" (default: "{value}").
+	Template string `mapstructure:"template"`
+	// AllowedNames restricts the resolved event names. Names that are not listed fall back
+	// to Default. An empty list allows any valid name.
+	AllowedNames []string `mapstructure:"allowed_names"`
+	// Default is the event name used when the attribute is missing, or the resolved name
+	// is not allowed. When empty, such records keep the encoder's default event.
+	Default string `mapstructure:"default"`
+}
+
+// Validate checks if the event name routing configuration is valid.
+func (c *EventNameConfig) Validate() error {
+	if c.Attribute == "" {
+		if c.Template != "" || len(c.AllowedNames) > 0 {
+			return errors.New(`"attribute" is required when "template" or "allowed_names" is set`)
+		}
+	}
+	switch c.Source {
+	case "", EventNameSourceRecord, EventNameSourceResource, EventNameSourceRecordThenResource:
+	default:
+		return fmt.Errorf(`invalid source %q (must be %q, %q or %q)`, c.Source,
+			EventNameSourceRecord, EventNameSourceResource, EventNameSourceRecordThenResource)
+	}
+	if c.Template != "" && !strings.Contains(c.Template, eventNameValuePlaceholder) {
+		return fmt.Errorf(`template %q must contain %q`, c.Template, eventNameValuePlaceholder)
+	}
+	for _, name := range c.AllowedNames {
+		if !isValidGenevaName(name) {
+			return fmt.Errorf(`invalid allowed event name %q`, name)
+		}
+	}
+	if c.Default != "" && !isValidGenevaName(c.Default) {
+		return fmt.Errorf(`invalid default event name %q`, c.Default)
+	}
+	return nil
+}
+
+// isValidGenevaName reports whether name is usable as a Geneva event or column name: it
+// must start with a letter and contain only letters, digits and underscores.
+func isValidGenevaName(name string) bool {
+	if name == "" || len(name) > maxGenevaNameLength {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '_'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeEventName replaces characters that are not allowed in Geneva event names with
+// underscores and truncates the result. It returns "" if no valid name can be derived.
+func sanitizeEventName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+		if b.Len() >= maxGenevaNameLength {
+			break
+		}
+	}
+	out := b.String()
+	if !isValidGenevaName(out) {
+		return ""
+	}
+	return out
+}
+
+// eventNameRouter resolves Geneva event names for log records and spans.
+type eventNameRouter struct {
+	cfg     EventNameConfig
+	allowed map[string]struct{}
+}
+
+// newEventNameRouter creates a router for cfg, or returns nil if routing is disabled.
+func newEventNameRouter(cfg EventNameConfig) *eventNameRouter {
+	if cfg.Attribute == "" && cfg.Default == "" {
+		return nil
+	}
+	if cfg.Source == "" {
+		cfg.Source = EventNameSourceRecordThenResource
+	}
+	if cfg.Template == "" {
+		cfg.Template = eventNameValuePlaceholder
+	}
+	r := &eventNameRouter{cfg: cfg}
+	if len(cfg.AllowedNames) > 0 {
+		r.allowed = make(map[string]struct{}, len(cfg.AllowedNames))
+		for _, name := range cfg.AllowedNames {
+			r.allowed[name] = struct{}{}
+		}
+	}
+	return r
+}
+
+// lookup returns the routing attribute value according to the configured source.
+func (r *eventNameRouter) lookup(resourceAttrs, recordAttrs pcommon.Map) (string, bool) {
+	if r.cfg.Attribute == "" {
+		return "", false
+	}
+	if r.cfg.Source != EventNameSourceResource {
+		if v, ok := recordAttrs.Get(r.cfg.Attribute); ok && v.AsString() != "" {
+			return v.AsString(), true
+		}
+	}
+	if r.cfg.Source != EventNameSourceRecord {
+		if v, ok := resourceAttrs.Get(r.cfg.Attribute); ok && v.AsString() != "" {
+			return v.AsString(), true
+		}
+	}
+	return "", false
+}
+
+// resolve returns the event name for a record or span, or "" if it should keep the
+// encoder's default event.
+func (r *eventNameRouter) resolve(resourceAttrs, recordAttrs pcommon.Map) string {
+	value, ok := r.lookup(resourceAttrs, recordAttrs)
+	if !ok {
+		return r.cfg.Default
+	}
+	name := sanitizeEventName(strings.ReplaceAll(r.cfg.Template, eventNameValuePlaceholder, value))
+	if name == "" {
+		return r.cfg.Default
+	}
+	if r.allowed != nil {
+		if _, ok := r.allowed[name]; !ok {
+			return r.cfg.Default
+		}
+	}
+	return name
+}
+
+// routeLogs sets the event name of every log record in ld.
+func (r *eventNameRouter) routeLogs(ld plog.Logs) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		resourceAttrs := rl.Resource().Attributes()
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				lr := records.At(k)
+				if name := r.resolve(resourceAttrs, lr.Attributes()); name != "" {
+					lr.SetEventName(name)
+				}
+			}
+		}
+	}
+}
+
+// spanEventNames holds the event name of every span of a request, indexed by resource,
+// scope and span. "" keeps the encoder's fixed Span event.
+type spanEventNames [][][]string
+
+// routeTraces resolves the event name of every span in td. Spans have no event name
+// field, so the names are returned for the encoder rather than written to the spans.
+func (r *eventNameRouter) routeTraces(td ptrace.Traces) spanEventNames {
+	names := make(spanEventNames, td.ResourceSpans().Len())
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		resourceAttrs := rs.Resource().Attributes()
+		names[i] = make([][]string, rs.ScopeSpans().Len())
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			names[i][j] = make([]string, spans.Len())
+			for k := 0; k < spans.Len(); k++ {
+				names[i][j][k] = r.resolve(resourceAttrs, spans.At(k).Attributes())
+			}
+		}
+	}
+	return names
+}
+
+// distinct returns the distinct event names in the order they first appear.
+func (n spanEventNames) distinct() []string {
+	var out []string
+	seen := make(map[string]struct{})
+	for _, scopes := range n {
+		for _, spans := range scopes {
+			for _, name := range spans {
+				if _, ok := seen[name]; !ok {
+					seen[name] = struct{}{}
+					out = append(out, name)
+				}
+			}
+		}
+	}
+	return out
+}
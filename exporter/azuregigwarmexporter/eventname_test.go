@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestEventNameRouterResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      EventNameConfig
+		resource map[string]any
+		record   map[string]any
+		want     string
+	}{
+		{
+			name:     "RecordThenResource",
+			cfg:      EventNameConfig{Attribute: "svc"},
+			resource: map[string]any{"svc": "resource"},
+			record:   map[string]any{"svc": "record"},
+			want:     "record",
+		},
+		{
+			name:     "FallbackToResource",
+			cfg:      EventNameConfig{Attribute: "svc"},
+			resource: map[string]any{"svc": "resource"},
+			want:     "resource",
+		},
+		{
+			name:     "ResourceOnly",
+			cfg:      EventNameConfig{Attribute: "svc", Source: EventNameSourceResource},
+			resource: map[string]any{"svc": "resource"},
+			record:   map[string]any{"svc": "record"},
+			want:     "resource",
+		},
+		{
+			name:     "RecordOnly",
+			cfg:      EventNameConfig{Attribute: "svc", Source: EventNameSourceRecord, Default: "Fallback"},
+			resource: map[string]any{"svc": "resource"},
+			want:     "Fallback",
+		},
+		{
+			name:   "TemplateAndSanitize",
+			cfg:    EventNameConfig{Attribute: "svc", Template: "App_{value}"},
+			record: map[string]any{"svc": "check-out.v2"},
+			want:   "App_check_out_v2",
+		},
+		{
+			name:   "NotAllowed",
+			cfg:    EventNameConfig{Attribute: "svc", AllowedNames: []string{"payments"}, Default: "Other"},
+			record: map[string]any{"svc": "checkout"},
+			want:   "Other",
+		},
+		{
+			name:   "InvalidWithoutDefault",
+			cfg:    EventNameConfig{Attribute: "svc"},
+			record: map[string]any{"svc": "9lives"},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.cfg.Validate())
+			r := newEventNameRouter(tt.cfg)
+			require.NotNil(t, r)
+			resource, record := pcommon.NewMap(), pcommon.NewMap()
+			require.NoError(t, resource.FromRaw(tt.resource))
+			require.NoError(t, record.FromRaw(tt.record))
+			assert.Equal(t, tt.want, r.resolve(resource, record))
+		})
+	}
+}
+
+func TestEventNameRouterRouteLogs(t *testing.T) {
+	r := newEventNameRouter(EventNameConfig{Attribute: "svc"})
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().Attributes().PutStr("svc", "checkout")
+	records.AppendEmpty().SetEventName("Existing")
+
+	r.routeLogs(ld)
+	assert.Equal(t, "checkout", records.At(0).EventName())
+	assert.Equal(t, "Existing", records.At(1).EventName(), "records without a routed name keep their event name")
+}
+
+func TestEventNameRouterRouteTraces(t *testing.T) {
+	r := newEventNameRouter(EventNameConfig{Attribute: "svc"})
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("svc", "payments")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().Attributes().PutStr("svc", "checkout")
+	spans.AppendEmpty()
+	spans.AppendEmpty().Attributes().PutStr("svc", "checkout")
+	unrouted := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	unrouted.AppendEmpty()
+
+	names := r.routeTraces(td)
+	assert.Equal(t, spanEventNames{{{"checkout", "payments", "checkout"}}, {{""}}}, names)
+	assert.Equal(t, []string{"checkout", "payments", ""}, names.distinct())
+
+	// Chunks never mix events, even when the request is within the limits
+	plan, events := planTracesByEvent(td, 0, 0, names)
+	require.Equal(t, []string{"checkout", "payments", ""}, events)
+	require.Equal(t, 3, plan.chunks())
+	assert.Equal(t, 2, tracesChunk(td, plan, 0).SpanCount())
+	assert.Equal(t, 1, tracesChunk(td, plan, 1).SpanCount())
+	assert.Equal(t, 1, tracesChunk(td, plan, 2).SpanCount())
+
+	// Each event is split separately
+	plan, events = planTracesByEvent(td, 1, 0, names)
+	assert.Equal(t, []string{"checkout", "checkout", "payments", ""}, events)
+	assert.Equal(t, 4, plan.chunks())
+
+	// A single event within the limits stays in one piece
+	plan, events = planTracesByEvent(td, 0, 0, spanEventNames{{{"a", "a", "a"}}, {{"a"}}})
+	assert.Nil(t, plan)
+	assert.Equal(t, []string{"a"}, events)
+}
@@ -12,6 +12,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.uber.org/zap"
@@ -83,9 +84,11 @@ func overrideConfigFromEnv(cfg *Config, logger *zap.Logger) {
 // createDefaultConfig creates the default exporter configuration.
 func (f *factory) createDefaultConfig() component.Config {
 	return &Config{
+		TimeoutConfig:    exporterhelper.NewDefaultTimeoutConfig(),
 		QueueConfig:      exporterhelper.NewDefaultQueueConfig(),
 		RetryConfig:      configretry.NewDefaultBackOffConfig(),
 		BatchRetryConfig: NewDefaultBatchRetryConfig(),
+		BodyFlatten:      defaultBodyFlattenConfig(),
 	}
 }
 
@@ -99,22 +102,31 @@ func (f *factory) createLogsExporter(ctx context.Context, set exporter.Settings,
 	// Override config from environment variables with logging
 	overrideConfigFromEnv(cfg, set.Logger)
 
-	exp, err := newLogsExporter(ctx, set, cfg)
+	exp, err := newLogsExporter(ctx, set, cfg.logsConfig())
 	if err != nil {
 		return nil, err
 	}
 
 	// Wrap with exporterhelper to enable queuing and retries
-	return exporterhelper.NewLogs(
+	logs, err := exporterhelper.NewLogs(
 		ctx,
 		set,
 		cfg,
 		exp.pushLogs,
+		// The record policies (e.g. redaction) rewrite log records in place before queuing
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
+		exporterhelper.WithTimeout(cfg.TimeoutConfig),
 		exporterhelper.WithRetry(cfg.RetryConfig),
 		exporterhelper.WithQueue(cfg.QueueConfig),
 		exporterhelper.WithStart(exp.start),
 		exporterhelper.WithShutdown(exp.shutdown),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the record policies once per request, so that retries do not apply them again
+	return &policyLogs{Logs: logs, apply: exp.applyPolicies}, nil
 }
 
 // createTracesExporter creates a traces exporter based on the config.
@@ -127,20 +139,29 @@ func (f *factory) createTracesExporter(ctx context.Context, set exporter.Setting
 	// Override config from environment variables with logging
 	overrideConfigFromEnv(cfg, set.Logger)
 
-	exp, err := newTracesExporter(ctx, set, cfg)
+	exp, err := newTracesExporter(ctx, set, cfg.tracesConfig())
 	if err != nil {
 		return nil, err
 	}
 
 	// Wrap with exporterhelper to enable queuing and retries
-	return exporterhelper.NewTraces(
+	traces, err := exporterhelper.NewTraces(
 		ctx,
 		set,
 		cfg,
 		exp.pushTraces,
+		// The record policies (e.g. redaction) rewrite spans in place before queuing
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
+		exporterhelper.WithTimeout(cfg.TimeoutConfig),
 		exporterhelper.WithRetry(cfg.RetryConfig),
 		exporterhelper.WithQueue(cfg.QueueConfig),
 		exporterhelper.WithStart(exp.start),
 		exporterhelper.WithShutdown(exp.shutdown),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the record policies once per request, so that retries do not apply them again
+	return &policyTraces{Traces: traces, apply: exp.applyPolicies}, nil
 }
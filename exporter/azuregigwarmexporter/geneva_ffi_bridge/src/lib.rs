@@ -5,6 +5,638 @@
 
 pub use geneva_uploader_ffi::*;
 
+use std::ffi::c_char;
+use std::sync::atomic::{AtomicI64, Ordering};
+use std::sync::Mutex;
+
 // Re-export all FFI functions and types for easy access from Go
 // The geneva-uploader-ffi crate from the registry includes all necessary
 // header files and FFI bindings
+
+// Bridge-owned functions below are declared in internal/cgo/headers/geneva_bridge.h.
+// Return codes use the values of GenevaError in geneva_errors.h.
+const GENEVA_SUCCESS: i32 = 0;
+const GENEVA_INVALID_DATA: i32 = 4;
+const GENEVA_ERR_NULL_POINTER: i32 = 100;
+const GENEVA_ERR_EMPTY_INPUT: i32 = 101;
+const GENEVA_ERR_INDEX_OUT_OF_RANGE: i32 = 103;
+
+/// Borrowed view of one encoded batch. All pointers stay valid until the batches
+/// handle is freed with `geneva_batches_free`; callers must copy what they keep.
+#[repr(C)]
+pub struct GenevaBatchInfo {
+    pub data: *const u8,
+    pub data_len: usize,
+    pub event_name: *const u8,
+    pub event_name_len: usize,
+    pub schema_ids: *const u8,
+    pub schema_ids_len: usize,
+    pub start_time: u64,
+    pub end_time: u64,
+}
+
+/// Describes the batch at `index`: its compressed payload, event name, schema IDs and
+/// time range.
+///
+/// # Safety
+/// `batches` must be a live handle returned by an encode function and `out` must point
+/// to writable memory for a `GenevaBatchInfo`.
+#[no_mangle]
+pub unsafe extern "C" fn geneva_batch_info(
+    batches: *const EncodedBatchesHandle,
+    index: usize,
+    out: *mut GenevaBatchInfo,
+) -> i32 {
+    if batches.is_null() || out.is_null() {
+        return GENEVA_ERR_NULL_POINTER;
+    }
+    let Some(batch) = (*batches).batches().get(index) else {
+        return GENEVA_ERR_INDEX_OUT_OF_RANGE;
+    };
+    *out = GenevaBatchInfo {
+        data: batch.data.as_ptr(),
+        data_len: batch.data.len(),
+        event_name: batch.event_name.as_ptr(),
+        event_name_len: batch.event_name.len(),
+        schema_ids: batch.metadata.schema_ids.as_ptr(),
+        schema_ids_len: batch.metadata.schema_ids.len(),
+        start_time: batch.metadata.start_time,
+        end_time: batch.metadata.end_time,
+    };
+    GENEVA_SUCCESS
+}
+
+// Process-wide state, maintained by the tracked wrappers below and read by geneva_stats.
+static LIVE_CLIENTS: AtomicI64 = AtomicI64::new(0);
+static LIVE_BATCHES: AtomicI64 = AtomicI64::new(0);
+static BATCH_BYTES: AtomicI64 = AtomicI64::new(0);
+// Addresses of the live clients that have uploaded at least one batch successfully.
+static CLIENTS_UPLOADED: Mutex<Vec<usize>> = Mutex::new(Vec::new());
+
+/// Snapshot of the bridge's process-wide state.
+#[repr(C)]
+pub struct GenevaStats {
+    pub live_clients: i64,
+    pub live_batches: i64,
+    pub batch_bytes: i64,
+    pub clients_uploaded: i64,
+}
+
+/// Returns the number of bytes held by the batches of a batches handle.
+unsafe fn batches_bytes(batches: *const EncodedBatchesHandle) -> i64 {
+    (*batches)
+        .batches()
+        .iter()
+        .map(|b| b.data.len() + b.event_name.len() + b.metadata.schema_ids.len())
+        .sum::<usize>() as i64
+}
+
+/// Writes the current number of live client and batches handles, the bytes held by live
+/// batches and the number of live clients that have uploaded to `out`.
+///
+/// # Safety
+/// `out` must point to writable memory for a `GenevaStats`.
+#[no_mangle]
+pub unsafe extern "C" fn geneva_stats(out: *mut GenevaStats) -> i32 {
+    if out.is_null() {
+        return GENEVA_ERR_NULL_POINTER;
+    }
+    *out = GenevaStats {
+        live_clients: LIVE_CLIENTS.load(Ordering::Relaxed),
+        live_batches: LIVE_BATCHES.load(Ordering::Relaxed),
+        batch_bytes: BATCH_BYTES.load(Ordering::Relaxed),
+        clients_uploaded: CLIENTS_UPLOADED.lock().map_or(0, |w| w.len() as i64),
+    };
+    GENEVA_SUCCESS
+}
+
+/// `geneva_client_new` that counts the returned handle.
+///
+/// # Safety
+/// Same contract as `geneva_client_new`.
+#[no_mangle]
+pub unsafe extern "C" fn geneva_tracked_client_new(
+    config: *const GenevaConfig,
+    out_handle: *mut *mut GenevaClientHandle,
+    err_msg_out: *mut c_char,
+    err_msg_len: usize,
+) -> i32 {
+    let rc = geneva_client_new(config, out_handle, err_msg_out, err_msg_len) as i32;
+    if rc == GENEVA_SUCCESS {
+        LIVE_CLIENTS.fetch_add(1, Ordering::Relaxed);
+    }
+    rc
+}
+
+/// `geneva_client_free` that uncounts the handle.
+///
+/// # Safety
+/// Same contract as `geneva_client_free`.
+#[no_mangle]
+pub unsafe extern "C" fn geneva_tracked_client_free(handle: *mut GenevaClientHandle) {
+    if !handle.is_null() {
+        LIVE_CLIENTS.fetch_sub(1, Ordering::Relaxed);
+        if let Ok(mut uploaded) = CLIENTS_UPLOADED.lock() {
+            uploaded.retain(|&c| c != handle as usize);
+        }
+    }
+    geneva_client_free(handle);
+}
+
+/// `geneva_encode_and_compress_logs` that counts the returned batches handle.
+///
+/// # Safety
+/// Same contract as `geneva_encode_and_compress_logs`.
+#[no_mangle]
+pub unsafe extern "C" fn geneva_tracked_encode_and_compress_logs(
+    handle: *mut GenevaClientHandle,
+    data: *const u8,
+    data_len: usize,
+    out_batches: *mut *mut EncodedBatchesHandle,
+    err_msg_out: *mut c_char,
+    err_msg_len: usize,
+) -> i32 {
+    let rc = geneva_encode_and_compress_logs(
+        handle,
+        data,
+        data_len,
+        out_batches,
+        err_msg_out,
+        err_msg_len,
+    ) as i32;
+    if rc == GENEVA_SUCCESS {
+        LIVE_BATCHES.fetch_add(1, Ordering::Relaxed);
+        BATCH_BYTES.fetch_add(batches_bytes(*out_batches), Ordering::Relaxed);
+    }
+    rc
+}
+
+/// `geneva_encode_and_compress_spans` that counts the returned batches handle.
+///
+/// # Safety
+/// Same contract as `geneva_encode_and_compress_spans`.
+#[no_mangle]
+pub unsafe extern "C" fn geneva_tracked_encode_and_compress_spans(
+    handle: *mut GenevaClientHandle,
+    data: *const u8,
+    data_len: usize,
+    out_batches: *mut *mut EncodedBatchesHandle,
+    err_msg_out: *mut c_char,
+    err_msg_len: usize,
+) -> i32 {
+    let rc = geneva_encode_and_compress_spans(
+        handle,
+        data,
+        data_len,
+        out_batches,
+        err_msg_out,
+        err_msg_len,
+    ) as i32;
+    if rc == GENEVA_SUCCESS {
+        LIVE_BATCHES.fetch_add(1, Ordering::Relaxed);
+        BATCH_BYTES.fetch_add(batches_bytes(*out_batches), Ordering::Relaxed);
+    }
+    rc
+}
+
+/// `geneva_tracked_encode_and_compress_spans` that writes the spans to the event
+/// `event_name` instead of the fixed `Span` event. The span encoder has no event name
+/// input, so every batch is renamed after encoding: its event name and the name of each
+/// event entity in its payload.
+///
+/// # Safety
+/// Same contract as `geneva_encode_and_compress_spans`; `event_name` must point to
+/// `event_name_len` readable bytes.
+#[no_mangle]
+pub unsafe extern "C" fn geneva_tracked_encode_and_compress_spans_as(
+    handle: *mut GenevaClientHandle,
+    data: *const u8,
+    data_len: usize,
+    event_name: *const u8,
+    event_name_len: usize,
+    out_batches: *mut *mut EncodedBatchesHandle,
+    err_msg_out: *mut c_char,
+    err_msg_len: usize,
+) -> i32 {
+    if event_name.is_null() || out_batches.is_null() {
+        return GENEVA_ERR_NULL_POINTER;
+    }
+    if event_name_len == 0 {
+        return GENEVA_ERR_EMPTY_INPUT;
+    }
+    let Ok(name) = std::str::from_utf8(std::slice::from_raw_parts(event_name, event_name_len))
+    else {
+        write_err_msg(err_msg_out, err_msg_len, "event name is not valid UTF-8");
+        return GENEVA_INVALID_DATA;
+    };
+    let rc = geneva_encode_and_compress_spans(
+        handle,
+        data,
+        data_len,
+        out_batches,
+        err_msg_out,
+        err_msg_len,
+    ) as i32;
+    if rc != GENEVA_SUCCESS {
+        return rc;
+    }
+    for batch in (**out_batches).batches_mut() {
+        match decompress_payload(&batch.data).and_then(|blob| rename_events(&blob, name)) {
+            Ok(blob) => {
+                batch.data = compress_payload(&blob);
+                batch.event_name = name.to_string();
+            }
+            Err(e) => {
+                geneva_batches_free(*out_batches);
+                *out_batches = std::ptr::null_mut();
+                write_err_msg(
+                    err_msg_out,
+                    err_msg_len,
+                    &format!("failed to rename span batch: {e}"),
+                );
+                return GENEVA_INVALID_DATA;
+            }
+        }
+    }
+    // Count after renaming, so that geneva_tracked_batches_free subtracts the same bytes
+    LIVE_BATCHES.fetch_add(1, Ordering::Relaxed);
+    BATCH_BYTES.fetch_add(batches_bytes(*out_batches), Ordering::Relaxed);
+    GENEVA_SUCCESS
+}
+
+/// `geneva_batches_free` that uncounts the handle.
+///
+/// # Safety
+/// Same contract as `geneva_batches_free`.
+#[no_mangle]
+pub unsafe extern "C" fn geneva_tracked_batches_free(batches: *mut EncodedBatchesHandle) {
+    if !batches.is_null() {
+        LIVE_BATCHES.fetch_sub(1, Ordering::Relaxed);
+        BATCH_BYTES.fetch_sub(batches_bytes(batches), Ordering::Relaxed);
+    }
+    geneva_batches_free(batches);
+}
+
+/// `geneva_upload_batch_sync` that marks the client as holding a cached token once an
+/// upload succeeds.
+///
+/// # Safety
+/// Same contract as `geneva_upload_batch_sync`.
+#[no_mangle]
+pub unsafe extern "C" fn geneva_tracked_upload_batch_sync(
+    handle: *mut GenevaClientHandle,
+    batches: *const EncodedBatchesHandle,
+    index: usize,
+    err_msg_out: *mut c_char,
+    err_msg_len: usize,
+) -> i32 {
+    let rc = geneva_upload_batch_sync(handle, batches, index, err_msg_out, err_msg_len) as i32;
+    if rc == GENEVA_SUCCESS {
+        if let Ok(mut uploaded) = CLIENTS_UPLOADED.lock() {
+            if !uploaded.contains(&(handle as usize)) {
+                uploaded.push(handle as usize);
+            }
+        }
+    }
+    rc
+}
+
+/// Writes `msg` to a caller-provided error buffer, truncated and NUL-terminated.
+unsafe fn write_err_msg(out: *mut c_char, len: usize, msg: &str) {
+    if out.is_null() || len == 0 {
+        return;
+    }
+    let n = msg.len().min(len - 1);
+    std::ptr::copy_nonoverlapping(msg.as_ptr() as *const c_char, out, n);
+    *out.add(n) = 0;
+}
+
+// Uncompressed size of the LZ4 chunks of an encoded payload.
+const PAYLOAD_CHUNK_SIZE: usize = 64 * 1024;
+
+// Central blob entity types and the terminator that follows every entity.
+const ENTITY_SCHEMA: u16 = 0;
+const ENTITY_EVENT: u16 = 2;
+const ENTITY_TERMINATOR: u32 = 0xdeadc0de;
+
+/// Decompresses an encoded payload: a sequence of LZ4 blocks, each prefixed with its
+/// compressed length as a little-endian u32.
+fn decompress_payload(src: &[u8]) -> Result<Vec<u8>, String> {
+    let mut r = Cursor { buf: src, off: 0 };
+    let mut out = Vec::with_capacity(src.len() * 4);
+    while r.off < src.len() {
+        let n = r.u32()? as usize;
+        let block = r.take(n)?;
+        let chunk = lz4_flex::block::decompress(block, PAYLOAD_CHUNK_SIZE)
+            .map_err(|e| format!("chunk at offset {}: {e}", r.off - n - 4))?;
+        out.extend_from_slice(&chunk);
+    }
+    Ok(out)
+}
+
+/// Compresses a central blob the way the encoder does: in chunks of at most
+/// `PAYLOAD_CHUNK_SIZE` bytes, each prefixed with its compressed length.
+fn compress_payload(src: &[u8]) -> Vec<u8> {
+    let mut out = Vec::with_capacity(src.len() / 2);
+    for chunk in src.chunks(PAYLOAD_CHUNK_SIZE) {
+        let block = lz4_flex::block::compress(chunk);
+        out.extend_from_slice(&(block.len() as u32).to_le_bytes());
+        out.extend_from_slice(&block);
+    }
+    out
+}
+
+/// Returns `blob` with every event entity renamed to `name`. A central blob is
+/// version u32 | format u32 | metadata (u32 length, bytes), followed by entities
+/// type u16 | body | terminator u32, where a schema body is id u64 | md5 [16] | schema
+/// (u32 length, bytes) and an event body is schema id u64 | level u8 | name (u16 length in
+/// bytes, UTF-16LE) | row (u32 length, bytes).
+fn rename_events(blob: &[u8], name: &str) -> Result<Vec<u8>, String> {
+    let name: Vec<u8> = name.encode_utf16().flat_map(u16::to_le_bytes).collect();
+    let name_len = u16::try_from(name.len()).map_err(|_| "event name too long".to_string())?;
+
+    let mut r = Cursor { buf: blob, off: 0 };
+    r.take(8)?; // version and format
+    let meta_len = r.u32()? as usize;
+    r.take(meta_len)?;
+    let mut out = Vec::with_capacity(blob.len() + name.len());
+    out.extend_from_slice(&blob[..r.off]);
+
+    while r.off < blob.len() {
+        let start = r.off;
+        match r.u16()? {
+            ENTITY_SCHEMA => {
+                r.take(8 + 16)?;
+                let n = r.u32()? as usize;
+                r.take(n)?;
+                r.terminator(start)?;
+                out.extend_from_slice(&blob[start..r.off]);
+            }
+            ENTITY_EVENT => {
+                r.take(8 + 1)?; // schema id and level
+                out.extend_from_slice(&blob[start..r.off]);
+                let old_len = r.u16()? as usize;
+                r.take(old_len)?;
+                out.extend_from_slice(&name_len.to_le_bytes());
+                out.extend_from_slice(&name);
+                let row = r.off;
+                let n = r.u32()? as usize;
+                r.take(n)?;
+                r.terminator(start)?;
+                out.extend_from_slice(&blob[row..r.off]);
+            }
+            t => return Err(format!("unknown entity type {t} at offset {start}")),
+        }
+    }
+    Ok(out)
+}
+
+/// Little-endian reader over a byte slice.
+struct Cursor<'a> {
+    buf: &'a [u8],
+    off: usize,
+}
+
+impl<'a> Cursor<'a> {
+    fn take(&mut self, n: usize) -> Result<&'a [u8], String> {
+        let end = self
+            .off
+            .checked_add(n)
+            .filter(|&end| end <= self.buf.len())
+            .ok_or_else(|| format!("truncated at offset {}", self.off))?;
+        let b = &self.buf[self.off..end];
+        self.off = end;
+        Ok(b)
+    }
+
+    fn u16(&mut self) -> Result<u16, String> {
+        Ok(u16::from_le_bytes(self.take(2)?.try_into().unwrap()))
+    }
+
+    fn u32(&mut self) -> Result<u32, String> {
+        Ok(u32::from_le_bytes(self.take(4)?.try_into().unwrap()))
+    }
+
+    fn terminator(&mut self, entity: usize) -> Result<(), String> {
+        if self.u32()? != ENTITY_TERMINATOR {
+            return Err(format!(
+                "missing terminator after entity at offset {entity}"
+            ));
+        }
+        Ok(())
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn utf16(s: &str) -> Vec<u8> {
+        s.encode_utf16().flat_map(u16::to_le_bytes).collect()
+    }
+
+    /// An event entity: schema id, level, name and row.
+    #[derive(Debug, PartialEq)]
+    struct Event {
+        schema_id: u64,
+        level: u8,
+        name: String,
+        row: Vec<u8>,
+    }
+
+    fn event(schema_id: u64, level: u8, name: &str, row: Vec<u8>) -> Event {
+        Event {
+            schema_id,
+            level,
+            name: name.to_string(),
+            row,
+        }
+    }
+
+    /// Builds a central blob with the layout documented on `rename_events`.
+    fn blob(metadata: &str, schemas: &[(u64, Vec<u8>)], events: &[Event]) -> Vec<u8> {
+        let mut b = Vec::new();
+        b.extend_from_slice(&1u32.to_le_bytes());
+        b.extend_from_slice(&2u32.to_le_bytes());
+        let meta = utf16(metadata);
+        b.extend_from_slice(&(meta.len() as u32).to_le_bytes());
+        b.extend_from_slice(&meta);
+        for (id, schema) in schemas {
+            b.extend_from_slice(&ENTITY_SCHEMA.to_le_bytes());
+            b.extend_from_slice(&id.to_le_bytes());
+            b.extend_from_slice(&[0xab; 16]);
+            b.extend_from_slice(&(schema.len() as u32).to_le_bytes());
+            b.extend_from_slice(schema);
+            b.extend_from_slice(&ENTITY_TERMINATOR.to_le_bytes());
+        }
+        for e in events {
+            b.extend_from_slice(&ENTITY_EVENT.to_le_bytes());
+            b.extend_from_slice(&e.schema_id.to_le_bytes());
+            b.push(e.level);
+            let name = utf16(&e.name);
+            b.extend_from_slice(&(name.len() as u16).to_le_bytes());
+            b.extend_from_slice(&name);
+            b.extend_from_slice(&(e.row.len() as u32).to_le_bytes());
+            b.extend_from_slice(&e.row);
+            b.extend_from_slice(&ENTITY_TERMINATOR.to_le_bytes());
+        }
+        b
+    }
+
+    /// Parses a central blob into its metadata bytes, schema entities and events.
+    fn parse(blob: &[u8]) -> (Vec<u8>, Vec<Vec<u8>>, Vec<Event>) {
+        let mut r = Cursor { buf: blob, off: 0 };
+        r.take(8).unwrap();
+        let meta_len = r.u32().unwrap() as usize;
+        let meta = r.take(meta_len).unwrap().to_vec();
+        let (mut schemas, mut events) = (Vec::new(), Vec::new());
+        while r.off < blob.len() {
+            let start = r.off;
+            match r.u16().unwrap() {
+                ENTITY_SCHEMA => {
+                    r.take(8 + 16).unwrap();
+                    let n = r.u32().unwrap() as usize;
+                    r.take(n).unwrap();
+                    r.terminator(start).unwrap();
+                    schemas.push(blob[start..r.off].to_vec());
+                }
+                ENTITY_EVENT => {
+                    let schema_id = u64::from_le_bytes(r.take(8).unwrap().try_into().unwrap());
+                    let level = r.take(1).unwrap()[0];
+                    let n = r.u16().unwrap() as usize;
+                    let units: Vec<u16> = r
+                        .take(n)
+                        .unwrap()
+                        .chunks(2)
+                        .map(|c| u16::from_le_bytes([c[0], c[1]]))
+                        .collect();
+                    let n = r.u32().unwrap() as usize;
+                    let row = r.take(n).unwrap().to_vec();
+                    r.terminator(start).unwrap();
+                    events.push(Event {
+                        schema_id,
+                        level,
+                        name: String::from_utf16(&units).unwrap(),
+                        row,
+                    });
+                }
+                t => panic!("unknown entity type {t}"),
+            }
+        }
+        (meta, schemas, events)
+    }
+
+    fn test_blob() -> (Vec<u8>, Vec<Event>) {
+        let events = vec![
+            event(1, 4, "Span", vec![1, 2, 3]),
+            event(2, 4, "Span", vec![]),
+            event(1, 2, "Span", (0..=255).collect()),
+        ];
+        let b = blob(
+            "namespace=Test",
+            &[(1, vec![9; 40]), (2, vec![8; 12])],
+            &events,
+        );
+        (b, events)
+    }
+
+    #[test]
+    fn payload_round_trip() {
+        // Three chunks, the last one partial
+        let blob: Vec<u8> = (0..2 * PAYLOAD_CHUNK_SIZE + 100)
+            .map(|i| (i % 251) as u8)
+            .collect();
+        let payload = compress_payload(&blob);
+        let mut r = Cursor {
+            buf: &payload,
+            off: 0,
+        };
+        let mut chunks = 0;
+        while r.off < payload.len() {
+            let n = r.u32().unwrap() as usize;
+            r.take(n).unwrap();
+            chunks += 1;
+        }
+        assert_eq!(chunks, 3);
+        assert_eq!(decompress_payload(&payload).unwrap(), blob);
+        assert_eq!(decompress_payload(&[]).unwrap(), Vec::<u8>::new());
+    }
+
+    #[test]
+    fn rename_multi_row_multi_event() {
+        let (b, events) = test_blob();
+        let renamed = rename_events(&b, "Spans_checkout").unwrap();
+
+        let (meta, schemas, got) = parse(&renamed);
+        let (want_meta, want_schemas, _) = parse(&b);
+        assert_eq!(meta, want_meta);
+        assert_eq!(schemas, want_schemas);
+        assert_eq!(got.len(), events.len());
+        for (got, want) in got.iter().zip(&events) {
+            assert_eq!(got.name, "Spans_checkout");
+            assert_eq!(
+                (got.schema_id, got.level, &got.row),
+                (want.schema_id, want.level, &want.row)
+            );
+        }
+
+        // Renaming is repeatable and to a shorter name shrinks the blob
+        let again = rename_events(&renamed, "S").unwrap();
+        assert!(parse(&again).2.iter().all(|e| e.name == "S"));
+        assert!(again.len() < b.len());
+    }
+
+    #[test]
+    fn rename_through_payload() {
+        let (b, events) = test_blob();
+        let payload = compress_payload(&b);
+        let renamed = rename_events(&decompress_payload(&payload).unwrap(), "Routed").unwrap();
+        let blob = decompress_payload(&compress_payload(&renamed)).unwrap();
+        let got = parse(&blob).2;
+        assert_eq!(got.len(), events.len());
+        assert!(got.iter().all(|e| e.name == "Routed"));
+    }
+
+    #[test]
+    fn rename_decoder_golden_payload() {
+        // The golden payload of the Go decoder tests: two rows of one schema and a row of a
+        // missing schema, compressed as literal-only LZ4 blocks
+        let payload = include_bytes!("../../genevapayload/testdata/log_batch.bin");
+        let b = decompress_payload(payload).unwrap();
+        let (meta, schemas, before) = parse(&b);
+        assert_eq!(before.len(), 3);
+
+        let renamed = parse(&rename_events(&b, "Spans_v2").unwrap());
+        assert_eq!(renamed.0, meta);
+        assert_eq!(renamed.1, schemas);
+        for (got, want) in renamed.2.iter().zip(&before) {
+            assert_eq!(got.name, "Spans_v2");
+            assert_eq!(got.row, want.row);
+        }
+    }
+
+    #[test]
+    fn rename_rejects_malformed_blobs() {
+        let (b, _) = test_blob();
+        assert!(rename_events(&b[..b.len() - 1], "X")
+            .unwrap_err()
+            .contains("truncated"));
+
+        let mut unknown = b.clone();
+        let first_entity = 12 + utf16("namespace=Test").len();
+        unknown[first_entity] = 7;
+        assert!(rename_events(&unknown, "X")
+            .unwrap_err()
+            .contains("unknown entity type 7"));
+
+        let mut unterminated = b.clone();
+        let last = unterminated.len() - 1;
+        unterminated[last] = 0;
+        assert!(rename_events(&unterminated, "X")
+            .unwrap_err()
+            .contains("missing terminator"));
+
+        let long = "x".repeat(40000);
+        assert_eq!(rename_events(&b, &long).unwrap_err(), "event name too long");
+    }
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genevapayload
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	blobVersion = 1
+	blobFormat  = 2
+
+	entitySchema = 0
+	entityEvent  = 2
+
+	entityTerminator = 0xdeadc0de
+)
+
+// Blob is a decoded Geneva central blob: the uncompressed body of an uploaded batch.
+type Blob struct {
+	Metadata string
+	Schemas  []Schema
+	Events   []Event
+}
+
+// Schema is a schema entity of a central blob.
+type Schema struct {
+	ID  uint64
+	MD5 [16]byte
+	Def *SchemaDef
+}
+
+// Event is an event (row) entity of a central blob.
+type Event struct {
+	SchemaID uint64
+	Level    uint8
+	Name     string
+	Row      []byte
+}
+
+// ParseBlob decodes a central blob. It is laid out as
+//
+//	version u32 | format u32 | metadata (u32 length in bytes, UTF-16LE)
+//	entities: type u16 | body | terminator u32 (0xdeadc0de)
+//
+// where a schema body is id u64 | md5 [16]byte | schema (u32 length, Compact Binary
+// SchemaDef) and an event body is schema id u64 | level u8 | name (u16 length in bytes,
+// UTF-16LE) | row (u32 length, Simple Binary).
+func ParseBlob(b []byte) (*Blob, error) {
+	r := &reader{buf: b}
+	version, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	format, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if version != blobVersion || format != blobFormat {
+		return nil, fmt.Errorf("unsupported Blob version %d / format %d (want %d / %d)", version, format, blobVersion, blobFormat)
+	}
+	metaLen, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if metaLen%2 != 0 {
+		return nil, errors.New("metadata length is not a multiple of 2")
+	}
+	out := &Blob{}
+	if out.Metadata, err = r.utf16String(int(metaLen / 2)); err != nil {
+		return nil, fmt.Errorf("metadata: %w", err)
+	}
+
+	for r.remaining() > 0 {
+		start := r.off
+		typ, err := r.u16()
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case entitySchema:
+			s, err := readBlobSchema(r)
+			if err != nil {
+				return nil, fmt.Errorf("schema entity at offset %d: %w", start, err)
+			}
+			out.Schemas = append(out.Schemas, s)
+		case entityEvent:
+			e, err := readBlobEvent(r)
+			if err != nil {
+				return nil, fmt.Errorf("event entity at offset %d: %w", start, err)
+			}
+			out.Events = append(out.Events, e)
+		default:
+			return nil, fmt.Errorf("unknown entity type %d at offset %d", typ, start)
+		}
+		term, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		if term != entityTerminator {
+			return nil, fmt.Errorf("missing terminator after entity at offset %d", start)
+		}
+	}
+	return out, nil
+}
+
+func readBlobSchema(r *reader) (Schema, error) {
+	var s Schema
+	var err error
+	if s.ID, err = r.u64(); err != nil {
+		return s, err
+	}
+	md5, err := r.bytes(16)
+	if err != nil {
+		return s, err
+	}
+	copy(s.MD5[:], md5)
+	n, err := r.u32()
+	if err != nil {
+		return s, err
+	}
+	def, err := r.bytes(int(n))
+	if err != nil {
+		return s, err
+	}
+	s.Def, err = parseSchemaDef(def)
+	return s, err
+}
+
+func readBlobEvent(r *reader) (Event, error) {
+	var e Event
+	var err error
+	if e.SchemaID, err = r.u64(); err != nil {
+		return e, err
+	}
+	if e.Level, err = r.u8(); err != nil {
+		return e, err
+	}
+	nameLen, err := r.u16()
+	if err != nil {
+		return e, err
+	}
+	if e.Name, err = r.utf16String(int(nameLen / 2)); err != nil {
+		return e, err
+	}
+	n, err := r.u32()
+	if err != nil {
+		return e, err
+	}
+	e.Row, err = r.bytes(int(n))
+	return e, err
+}
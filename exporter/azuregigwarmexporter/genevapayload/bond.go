@@ -0,0 +1,506 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genevapayload
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf16"
+)
+
+// Bond data types (BondDataType).
+const (
+	btStop     = 0
+	btStopBase = 1
+	btBool     = 2
+	btUint8    = 3
+	btUint16   = 4
+	btUint32   = 5
+	btUint64   = 6
+	btFloat    = 7
+	btDouble   = 8
+	btString   = 9
+	btStruct   = 10
+	btList     = 11
+	btSet      = 12
+	btMap      = 13
+	btInt8     = 14
+	btInt16    = 15
+	btInt32    = 16
+	btInt64    = 17
+	btWString  = 18
+)
+
+var errTruncated = errors.New("unexpected end of data")
+
+// reader reads little-endian and variable-length encoded values from a byte slice.
+type reader struct {
+	buf []byte
+	off int
+}
+
+func (r *reader) remaining() int { return len(r.buf) - r.off }
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > r.remaining() {
+		return nil, errTruncated
+	}
+	b := r.buf[r.off : r.off+n]
+	r.off += n
+	return b, nil
+}
+
+func (r *reader) u8() (uint8, error) {
+	b, err := r.bytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (r *reader) u16() (uint16, error) {
+	b, err := r.bytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	b, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (r *reader) u64() (uint64, error) {
+	b, err := r.bytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (r *reader) varint() (uint64, error) {
+	var v uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		b, err := r.u8()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return v, nil
+		}
+	}
+	return 0, errors.New("varint overflows 64 bits")
+}
+
+func (r *reader) zigzag() (int64, error) {
+	v, err := r.varint()
+	if err != nil {
+		return 0, err
+	}
+	return int64(v>>1) ^ -int64(v&1), nil
+}
+
+// utf16String reads n UTF-16LE code units.
+func (r *reader) utf16String(n int) (string, error) {
+	b, err := r.bytes(2 * n)
+	if err != nil {
+		return "", err
+	}
+	units := make([]uint16, n)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// compactStruct is a struct decoded from Compact Binary v1 without a schema: field id to
+// value. Base struct fields are merged into the same map.
+type compactStruct map[uint16]any
+
+// readCompactStruct decodes a Compact Binary v1 struct.
+func (r *reader) readCompactStruct() (compactStruct, error) {
+	s := make(compactStruct)
+	for {
+		header, err := r.u8()
+		if err != nil {
+			return nil, err
+		}
+		typ := header & 0x1f
+		id := uint16(header >> 5)
+		switch id {
+		case 6:
+			b, err := r.u8()
+			if err != nil {
+				return nil, err
+			}
+			id = uint16(b)
+		case 7:
+			if id, err = r.u16(); err != nil {
+				return nil, err
+			}
+		}
+		switch typ {
+		case btStop:
+			return s, nil
+		case btStopBase:
+			continue
+		}
+		v, err := r.readCompactValue(typ)
+		if err != nil {
+			return nil, fmt.Errorf("field %d: %w", id, err)
+		}
+		s[id] = v
+	}
+}
+
+// readCompactValue decodes one Compact Binary v1 value of the given type.
+func (r *reader) readCompactValue(typ uint8) (any, error) {
+	switch typ {
+	case btBool:
+		b, err := r.u8()
+		return b != 0, err
+	case btUint8:
+		return r.u8()
+	case btInt8:
+		b, err := r.u8()
+		return int8(b), err
+	case btUint16, btUint32, btUint64:
+		return r.varint()
+	case btInt16, btInt32, btInt64:
+		return r.zigzag()
+	case btFloat:
+		v, err := r.u32()
+		return math.Float32frombits(v), err
+	case btDouble:
+		v, err := r.u64()
+		return math.Float64frombits(v), err
+	case btString:
+		n, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.bytes(int(n))
+		return string(b), err
+	case btWString:
+		n, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		return r.utf16String(int(n))
+	case btStruct:
+		return r.readCompactStruct()
+	case btList, btSet:
+		elemType, err := r.u8()
+		if err != nil {
+			return nil, err
+		}
+		n, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(r.remaining()) {
+			return nil, errTruncated
+		}
+		list := make([]any, 0, n)
+		for i := uint64(0); i < n; i++ {
+			v, err := r.readCompactValue(elemType & 0x1f)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case btMap:
+		keyType, err := r.u8()
+		if err != nil {
+			return nil, err
+		}
+		valueType, err := r.u8()
+		if err != nil {
+			return nil, err
+		}
+		n, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(r.remaining()) {
+			return nil, errTruncated
+		}
+		m := make(map[string]any, n)
+		for i := uint64(0); i < n; i++ {
+			k, err := r.readCompactValue(keyType & 0x1f)
+			if err != nil {
+				return nil, err
+			}
+			v, err := r.readCompactValue(valueType & 0x1f)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(k)] = v
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unknown bond type %d", typ)
+	}
+}
+
+// typeDef is a decoded Bond TypeDef.
+type typeDef struct {
+	id        uint8
+	structDef int
+	element   *typeDef
+	key       *typeDef
+}
+
+// FieldDef is a decoded Bond FieldDef.
+type FieldDef struct {
+	Name string `json:"name"`
+	ID   uint16 `json:"id"`
+	Type string `json:"type"`
+	typ  typeDef
+}
+
+// StructDef is a decoded Bond StructDef.
+type StructDef struct {
+	Name   string     `json:"name"`
+	Fields []FieldDef `json:"fields"`
+}
+
+// SchemaDef is a decoded Bond SchemaDef.
+type SchemaDef struct {
+	Structs []StructDef `json:"structs"`
+	root    typeDef
+}
+
+// parseSchemaDef decodes a Compact Binary v1 encoded SchemaDef.
+func parseSchemaDef(b []byte) (*SchemaDef, error) {
+	r := &reader{buf: b}
+	raw, err := r.readCompactStruct()
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	s := &SchemaDef{root: toTypeDef(raw[1])}
+	structs, _ := raw[0].([]any)
+	for _, rawStruct := range structs {
+		st, _ := rawStruct.(compactStruct)
+		def := StructDef{Name: metadataName(st[0])}
+		fields, _ := st[2].([]any)
+		for _, rawField := range fields {
+			f, _ := rawField.(compactStruct)
+			fd := FieldDef{Name: metadataName(f[0]), typ: toTypeDef(f[2])}
+			if id, ok := f[1].(uint64); ok {
+				fd.ID = uint16(id)
+			}
+			fd.Type = typeName(fd.typ)
+			def.Fields = append(def.Fields, fd)
+		}
+		s.Structs = append(s.Structs, def)
+	}
+	if len(s.Structs) == 0 {
+		return nil, errors.New("invalid schema: no structs")
+	}
+	return s, nil
+}
+
+// metadataName returns the name of a decoded Bond Metadata struct.
+func metadataName(v any) string {
+	m, _ := v.(compactStruct)
+	name, _ := m[0].(string)
+	return name
+}
+
+// toTypeDef converts a decoded Bond TypeDef struct. Missing fields keep their defaults
+// (BT_STRUCT, struct 0).
+func toTypeDef(v any) typeDef {
+	m, _ := v.(compactStruct)
+	t := typeDef{id: btStruct}
+	switch id := m[0].(type) {
+	case int64:
+		t.id = uint8(id)
+	case uint64:
+		t.id = uint8(id)
+	}
+	if sd, ok := m[1].(uint64); ok {
+		t.structDef = int(sd)
+	}
+	if e, ok := m[2].(compactStruct); ok {
+		et := toTypeDef(e)
+		t.element = &et
+	}
+	if k, ok := m[3].(compactStruct); ok {
+		kt := toTypeDef(k)
+		t.key = &kt
+	}
+	return t
+}
+
+// typeName returns a readable name for a Bond type.
+func typeName(t typeDef) string {
+	names := map[uint8]string{
+		btBool: "bool", btUint8: "uint8", btUint16: "uint16", btUint32: "uint32", btUint64: "uint64",
+		btFloat: "float", btDouble: "double", btString: "string", btStruct: "struct", btList: "list",
+		btSet: "set", btMap: "map", btInt8: "int8", btInt16: "int16", btInt32: "int32", btInt64: "int64",
+		btWString: "wstring",
+	}
+	name, ok := names[t.id]
+	if !ok {
+		return fmt.Sprintf("type(%d)", t.id)
+	}
+	switch {
+	case (t.id == btList || t.id == btSet) && t.element != nil:
+		return name + "<" + typeName(*t.element) + ">"
+	case t.id == btMap && t.key != nil && t.element != nil:
+		return name + "<" + typeName(*t.key) + "," + typeName(*t.element) + ">"
+	}
+	return name
+}
+
+// Column is one decoded field of a row.
+type Column struct {
+	Name  string
+	Value any
+}
+
+// DecodeRow decodes a row written with the Bond Simple Binary v1 protocol: the fields
+// of the root struct in schema order, without field headers.
+func (s *SchemaDef) DecodeRow(b []byte) ([]Column, error) {
+	r := &reader{buf: b}
+	cols, err := s.readSimpleStruct(r, s.root.structDef, 0)
+	if err != nil {
+		return cols, err
+	}
+	if r.remaining() != 0 {
+		return cols, fmt.Errorf("%d trailing bytes after row", r.remaining())
+	}
+	return cols, nil
+}
+
+func (s *SchemaDef) readSimpleStruct(r *reader, idx, depth int) ([]Column, error) {
+	if idx < 0 || idx >= len(s.Structs) {
+		return nil, fmt.Errorf("struct index %d out of range", idx)
+	}
+	if depth > 32 {
+		return nil, errors.New("struct nesting too deep")
+	}
+	fields := s.Structs[idx].Fields
+	cols := make([]Column, 0, len(fields))
+	for _, f := range fields {
+		v, err := s.readSimpleValue(r, f.typ, depth)
+		if err != nil {
+			return cols, fmt.Errorf("field %q: %w", f.Name, err)
+		}
+		cols = append(cols, Column{Name: f.Name, Value: v})
+	}
+	return cols, nil
+}
+
+func (s *SchemaDef) readSimpleValue(r *reader, t typeDef, depth int) (any, error) {
+	switch t.id {
+	case btBool:
+		b, err := r.u8()
+		return b != 0, err
+	case btUint8:
+		return r.u8()
+	case btInt8:
+		b, err := r.u8()
+		return int8(b), err
+	case btUint16:
+		return r.u16()
+	case btInt16:
+		v, err := r.u16()
+		return int16(v), err
+	case btUint32:
+		return r.u32()
+	case btInt32:
+		v, err := r.u32()
+		return int32(v), err
+	case btUint64:
+		return r.u64()
+	case btInt64:
+		v, err := r.u64()
+		return int64(v), err
+	case btFloat:
+		v, err := r.u32()
+		return math.Float32frombits(v), err
+	case btDouble:
+		v, err := r.u64()
+		return math.Float64frombits(v), err
+	case btString:
+		n, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.bytes(int(n))
+		return string(b), err
+	case btWString:
+		n, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		return r.utf16String(int(n))
+	case btStruct:
+		cols, err := s.readSimpleStruct(r, t.structDef, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]any, len(cols))
+		for _, c := range cols {
+			m[c.Name] = c.Value
+		}
+		return m, nil
+	case btList, btSet:
+		if t.element == nil {
+			return nil, errors.New("list without element type")
+		}
+		n, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		if int(n) > r.remaining() {
+			return nil, errTruncated
+		}
+		list := make([]any, 0, n)
+		for i := uint32(0); i < n; i++ {
+			v, err := s.readSimpleValue(r, *t.element, depth)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case btMap:
+		if t.key == nil || t.element == nil {
+			return nil, errors.New("map without key or value type")
+		}
+		n, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		if int(n) > r.remaining() {
+			return nil, errTruncated
+		}
+		m := make(map[string]any, n)
+		for i := uint32(0); i < n; i++ {
+			k, err := s.readSimpleValue(r, *t.key, depth)
+			if err != nil {
+				return nil, err
+			}
+			v, err := s.readSimpleValue(r, *t.element, depth)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(k)] = v
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unsupported bond type %d", t.id)
+	}
+}
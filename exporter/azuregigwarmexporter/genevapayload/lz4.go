@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genevapayload
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// maxChunkSize is the size of the uncompressed chunks the encoder compresses separately.
+const maxChunkSize = 64 * 1024
+
+// Decompress decompresses a Geneva payload: a sequence of LZ4 blocks, each
+// prefixed with its compressed length as a little-endian uint32, that decompress to at
+// most maxChunkSize bytes each.
+func Decompress(src []byte) ([]byte, error) {
+	out := make([]byte, 0, 4*len(src))
+	for off := 0; off < len(src); {
+		if len(src)-off < 4 {
+			return nil, fmt.Errorf("truncated chunk header at offset %d", off)
+		}
+		n := int(binary.LittleEndian.Uint32(src[off:]))
+		off += 4
+		if n > len(src)-off {
+			return nil, fmt.Errorf("chunk at offset %d claims %d bytes, %d available", off-4, n, len(src)-off)
+		}
+		var err error
+		out, err = decompressBlock(out, src[off:off+n], maxChunkSize)
+		if err != nil {
+			return nil, fmt.Errorf("chunk at offset %d: %w", off-4, err)
+		}
+		off += n
+	}
+	return out, nil
+}
+
+var errCorruptBlock = errors.New("corrupt lz4 block")
+
+// decompressBlock appends the decompressed LZ4 block src to dst. The block may produce at
+// most limit bytes.
+func decompressBlock(dst, src []byte, limit int) ([]byte, error) {
+	start := len(dst)
+	for i := 0; i < len(src); {
+		token := src[i]
+		i++
+
+		// Literals
+		litLen := int(token >> 4)
+		if litLen == 15 {
+			for {
+				if i >= len(src) {
+					return nil, errCorruptBlock
+				}
+				b := src[i]
+				i++
+				litLen += int(b)
+				if b != 255 {
+					break
+				}
+			}
+		}
+		if litLen > len(src)-i || len(dst)-start+litLen > limit {
+			return nil, errCorruptBlock
+		}
+		dst = append(dst, src[i:i+litLen]...)
+		i += litLen
+		if i == len(src) {
+			// The last sequence has literals only
+			break
+		}
+
+		// Match
+		if len(src)-i < 2 {
+			return nil, errCorruptBlock
+		}
+		offset := int(binary.LittleEndian.Uint16(src[i:]))
+		i += 2
+		if offset == 0 || offset > len(dst)-start {
+			return nil, errCorruptBlock
+		}
+		matchLen := int(token & 0x0f)
+		if matchLen == 15 {
+			for {
+				if i >= len(src) {
+					return nil, errCorruptBlock
+				}
+				b := src[i]
+				i++
+				matchLen += int(b)
+				if b != 255 {
+					break
+				}
+			}
+		}
+		matchLen += 4
+		if len(dst)-start+matchLen > limit {
+			return nil, errCorruptBlock
+		}
+		// Copy byte by byte: the match may overlap the bytes it produces
+		pos := len(dst) - offset
+		for k := 0; k < matchLen; k++ {
+			dst = append(dst, dst[pos+k])
+		}
+	}
+	return dst, nil
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package genevapayload decodes the batches the azuregigwarm exporter uploads to Geneva:
+// LZ4 compressed central blobs whose schemas are Bond SchemaDefs (Compact Binary v1) and
+// whose rows are written with Bond Simple Binary v1.
+//
+// It is used by the gigwarmdecode command and by the testbed receiver to validate what
+// the exporter sent. It is a debugging aid, not a complete implementation of the formats.
+package genevapayload // import "github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter/genevapayload"
+
+import "fmt"
+
+// Row is an event of a blob decoded with its schema.
+type Row struct {
+	Event    string
+	SchemaID uint64
+	Level    uint8
+	// Columns are the fields of the row in schema order. When Err is set, they hold the
+	// fields decoded before the error.
+	Columns []Column
+	Err     error
+}
+
+// Value returns the value of the named column.
+func (r Row) Value(name string) (any, bool) {
+	for _, c := range r.Columns {
+		if c.Name == name {
+			return c.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Decode decompresses and parses an uploaded payload.
+func Decode(payload []byte) (*Blob, error) {
+	data, err := Decompress(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	return ParseBlob(data)
+}
+
+// Rows decodes the events of b with their schemas. Rows that fail to decode are returned
+// with Err set.
+func (b *Blob) Rows() []Row {
+	schemas := make(map[uint64]*SchemaDef, len(b.Schemas))
+	for _, s := range b.Schemas {
+		schemas[s.ID] = s.Def
+	}
+	rows := make([]Row, 0, len(b.Events))
+	for _, e := range b.Events {
+		row := Row{Event: e.Name, SchemaID: e.SchemaID, Level: e.Level}
+		if def, ok := schemas[e.SchemaID]; ok {
+			row.Columns, row.Err = def.DecodeRow(e.Row)
+		} else {
+			row.Err = fmt.Errorf("unknown schema id %d", e.SchemaID)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
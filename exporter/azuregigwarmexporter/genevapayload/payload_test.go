@@ -0,0 +1,439 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genevapayload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden payload in testdata")
+
+const goldenPayload = "log_batch.bin"
+
+// The test payload is written by the helpers below, which follow the layout documented on
+// ParseBlob and the Bond Compact/Simple Binary v1 specifications. It was not captured from
+// the Rust encoder; the golden file guards the decoder against regressions.
+
+// compactWriter writes Bond Compact Binary v1.
+type compactWriter []byte
+
+func (w *compactWriter) varint(v uint64) {
+	*w = binary.AppendUvarint(*w, v)
+}
+
+func (w *compactWriter) field(id, typ uint8) {
+	*w = append(*w, id<<5|typ)
+}
+
+func (w *compactWriter) stringField(id uint8, s string) {
+	w.field(id, btString)
+	w.varint(uint64(len(s)))
+	*w = append(*w, s...)
+}
+
+func (w *compactWriter) stop() {
+	*w = append(*w, btStop)
+}
+
+// typeDef writes the fields of a Bond TypeDef struct followed by its stop byte.
+func (w *compactWriter) typeDef(t typeDef) {
+	w.field(0, btInt32)
+	w.varint(uint64(t.id) << 1)
+	if t.id == btStruct {
+		w.field(1, btUint16)
+		w.varint(uint64(t.structDef))
+	}
+	if t.element != nil {
+		w.field(2, btStruct)
+		w.typeDef(*t.element)
+	}
+	if t.key != nil {
+		w.field(3, btStruct)
+		w.typeDef(*t.key)
+	}
+	w.stop()
+}
+
+type testField struct {
+	name string
+	typ  typeDef
+}
+
+// schemaDef returns a Compact Binary SchemaDef with one root struct.
+func schemaDef(name string, fields []testField) []byte {
+	var w compactWriter
+	w.field(0, btList)
+	w = append(w, btStruct)
+	w.varint(1)
+	// StructDef
+	w.field(0, btStruct)
+	w.stringField(0, name)
+	w.stop()
+	w.field(2, btList)
+	w = append(w, btStruct)
+	w.varint(uint64(len(fields)))
+	for i, f := range fields {
+		w.field(0, btStruct)
+		w.stringField(0, f.name)
+		w.stop()
+		w.field(1, btUint16)
+		w.varint(uint64(i))
+		w.field(2, btStruct)
+		w.typeDef(f.typ)
+		w.stop()
+	}
+	w.stop()
+	// Root TypeDef
+	w.field(1, btStruct)
+	w.typeDef(typeDef{id: btStruct})
+	w.stop()
+	return w
+}
+
+// simpleWriter writes Bond Simple Binary v1.
+type simpleWriter []byte
+
+func (w *simpleWriter) u8(v uint8)   { *w = append(*w, v) }
+func (w *simpleWriter) u32(v uint32) { *w = binary.LittleEndian.AppendUint32(*w, v) }
+func (w *simpleWriter) u64(v uint64) { *w = binary.LittleEndian.AppendUint64(*w, v) }
+
+func (w *simpleWriter) str(s string) {
+	w.u32(uint32(len(s)))
+	*w = append(*w, s...)
+}
+
+func (w *simpleWriter) wstr(s string) {
+	units := utf16.Encode([]rune(s))
+	w.u32(uint32(len(units)))
+	for _, u := range units {
+		*w = binary.LittleEndian.AppendUint16(*w, u)
+	}
+}
+
+func appendUTF16(b []byte, s string) []byte {
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	return b
+}
+
+type testEvent struct {
+	schemaID uint64
+	level    uint8
+	name     string
+	row      []byte
+}
+
+// centralBlob returns a central blob with the given schemas (by ID) and events.
+func centralBlob(metadata string, schemas map[uint64][]byte, ids []uint64, events []testEvent) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, blobVersion)
+	b = binary.LittleEndian.AppendUint32(b, blobFormat)
+	meta := appendUTF16(nil, metadata)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(meta)))
+	b = append(b, meta...)
+	for _, id := range ids {
+		b = binary.LittleEndian.AppendUint16(b, entitySchema)
+		b = binary.LittleEndian.AppendUint64(b, id)
+		b = append(b, make([]byte, 16)...)
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(schemas[id])))
+		b = append(b, schemas[id]...)
+		b = binary.LittleEndian.AppendUint32(b, entityTerminator)
+	}
+	for _, e := range events {
+		b = binary.LittleEndian.AppendUint16(b, entityEvent)
+		b = binary.LittleEndian.AppendUint64(b, e.schemaID)
+		b = append(b, e.level)
+		name := appendUTF16(nil, e.name)
+		b = binary.LittleEndian.AppendUint16(b, uint16(len(name)))
+		b = append(b, name...)
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(e.row)))
+		b = append(b, e.row...)
+		b = binary.LittleEndian.AppendUint32(b, entityTerminator)
+	}
+	return b
+}
+
+// compressLiterals compresses data into length-prefixed LZ4 blocks that hold literals only.
+func compressLiterals(data []byte) []byte {
+	var out []byte
+	for len(data) > 0 {
+		n := min(len(data), maxChunkSize)
+		var block []byte
+		if n < 15 {
+			block = append(block, byte(n<<4))
+		} else {
+			block = append(block, 0xf0)
+			rest := n - 15
+			for ; rest >= 255; rest -= 255 {
+				block = append(block, 255)
+			}
+			block = append(block, byte(rest))
+		}
+		block = append(block, data[:n]...)
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(block)))
+		out = append(out, block...)
+		data = data[n:]
+	}
+	return out
+}
+
+var (
+	stringType = typeDef{id: btString}
+	logFields  = []testField{
+		{"env_name", stringType},
+		{"env_time", stringType},
+		{"SeverityNumber", typeDef{id: btInt32}},
+		{"body", stringType},
+		{"retries", typeDef{id: btInt64}},
+		{"ratio", typeDef{id: btDouble}},
+		{"cached", typeDef{id: btBool}},
+		{"tags", typeDef{id: btList, element: &stringType}},
+		{"labels", typeDef{id: btMap, key: &stringType, element: &stringType}},
+		{"user", typeDef{id: btWString}},
+	}
+)
+
+func logRow(body string, severity int32, retries int64, tags []string) []byte {
+	var w simpleWriter
+	w.str("Log")
+	w.str("2025-06-01T12:00:00.000000000Z")
+	w.u32(uint32(severity))
+	w.str(body)
+	w.u64(uint64(retries))
+	w.u64(math.Float64bits(0.25))
+	w.u8(1)
+	w.u32(uint32(len(tags)))
+	for _, tag := range tags {
+		w.str(tag)
+	}
+	w.u32(1)
+	w.str("region")
+	w.str("westeurope")
+	w.wstr("grâce")
+	return w
+}
+
+// testBlob returns the central blob of the golden payload: two log rows and a row whose
+// schema is missing.
+func testBlob() []byte {
+	return centralBlob("namespace=Test/eventVersion=Ver1v0", map[uint64][]byte{
+		7: schemaDef("Log", logFields),
+	}, []uint64{7}, []testEvent{
+		{schemaID: 7, level: 4, name: "Log", row: logRow("hello", 9, -1, []string{"a", "b"})},
+		{schemaID: 7, level: 2, name: "Log", row: logRow("disk full", 17, 3, nil)},
+		{schemaID: 8, level: 4, name: "Audit", row: []byte{0}},
+	})
+}
+
+func wantRows() []Row {
+	columns := func(body string, severity int32, retries int64, tags []any) []Column {
+		return []Column{
+			{Name: "env_name", Value: "Log"},
+			{Name: "env_time", Value: "2025-06-01T12:00:00.000000000Z"},
+			{Name: "SeverityNumber", Value: severity},
+			{Name: "body", Value: body},
+			{Name: "retries", Value: retries},
+			{Name: "ratio", Value: 0.25},
+			{Name: "cached", Value: true},
+			{Name: "tags", Value: tags},
+			{Name: "labels", Value: map[string]any{"region": "westeurope"}},
+			{Name: "user", Value: "grâce"},
+		}
+	}
+	return []Row{
+		{Event: "Log", SchemaID: 7, Level: 4, Columns: columns("hello", 9, -1, []any{"a", "b"})},
+		{Event: "Log", SchemaID: 7, Level: 2, Columns: columns("disk full", 17, 3, []any{})},
+	}
+}
+
+func TestDecodeGolden(t *testing.T) {
+	path := filepath.Join("testdata", goldenPayload)
+	if *update {
+		require.NoError(t, os.WriteFile(path, compressLiterals(testBlob()), 0o600))
+	}
+	payload, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	b, err := Decode(payload)
+	require.NoError(t, err)
+	assert.Equal(t, "namespace=Test/eventVersion=Ver1v0", b.Metadata)
+	require.Len(t, b.Schemas, 1)
+	assert.Equal(t, uint64(7), b.Schemas[0].ID)
+	require.Len(t, b.Schemas[0].Def.Structs, 1)
+	assert.Equal(t, "Log", b.Schemas[0].Def.Structs[0].Name)
+	assert.Equal(t, FieldDef{Name: "labels", ID: 8, Type: "map<string,string>", typ: logFields[8].typ},
+		b.Schemas[0].Def.Structs[0].Fields[8])
+
+	rows := b.Rows()
+	require.Len(t, rows, 3)
+	assert.Equal(t, wantRows(), rows[:2])
+	assert.Equal(t, "Audit", rows[2].Event)
+	assert.EqualError(t, rows[2].Err, "unknown schema id 8")
+
+	v, ok := rows[0].Value("body")
+	assert.True(t, ok)
+	assert.Equal(t, "hello", v)
+	_, ok = rows[0].Value("missing")
+	assert.False(t, ok)
+}
+
+func TestDecompress(t *testing.T) {
+	// "ab", a match of 6 bytes at offset 2 that overlaps its own output, then "c"
+	block := []byte{0x22, 'a', 'b', 0x02, 0x00, 0x10, 'c'}
+	payload := binary.LittleEndian.AppendUint32(nil, uint32(len(block)))
+	payload = append(payload, block...)
+	out, err := Decompress(payload)
+	require.NoError(t, err)
+	assert.Equal(t, "ababababc", string(out))
+
+	// Blobs larger than a chunk are compressed in several blocks
+	data := make([]byte, maxChunkSize+300)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	out, err = Decompress(compressLiterals(data))
+	require.NoError(t, err)
+	assert.Equal(t, data, out)
+}
+
+func TestDecompressCorrupt(t *testing.T) {
+	block := func(b ...byte) []byte {
+		return append(binary.LittleEndian.AppendUint32(nil, uint32(len(b))), b...)
+	}
+	// "a" and a match that repeats it past the chunk size
+	tooLarge := append([]byte{0x1f, 'a', 0x01, 0x00}, bytes.Repeat([]byte{255}, maxChunkSize/255)...)
+	tooLarge = append(tooLarge, 0)
+
+	tests := []struct {
+		name    string
+		payload []byte
+		wantErr string
+	}{
+		{name: "TruncatedHeader", payload: []byte{1, 0}, wantErr: "truncated chunk header"},
+		{name: "ChunkTooLong", payload: append(binary.LittleEndian.AppendUint32(nil, 10), 0x10, 'a'), wantErr: "claims 10 bytes, 2 available"},
+		{name: "LiteralsTooLong", payload: block(0x50, 'a'), wantErr: "corrupt lz4 block"},
+		{name: "UnterminatedLength", payload: block(0xf0, 255), wantErr: "corrupt lz4 block"},
+		{name: "MissingOffset", payload: block(0x10, 'a', 0x02), wantErr: "corrupt lz4 block"},
+		{name: "ZeroOffset", payload: block(0x10, 'a', 0x00, 0x00), wantErr: "corrupt lz4 block"},
+		{name: "OffsetBeforeStart", payload: block(0x10, 'a', 0x02, 0x00), wantErr: "corrupt lz4 block"},
+		{name: "ChunkTooLarge", payload: block(tooLarge...), wantErr: "corrupt lz4 block"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decompress(tt.payload)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseBlobCorrupt(t *testing.T) {
+	blob := testBlob()
+	withByte := func(off int, v byte) []byte {
+		b := append([]byte(nil), blob...)
+		b[off] = v
+		return b
+	}
+	// The first entity starts after the version, format and metadata
+	metaLen := int(binary.LittleEndian.Uint32(blob[8:]))
+	firstEntity := 12 + metaLen
+	schemaLen := int(binary.LittleEndian.Uint32(blob[firstEntity+26:]))
+	firstTerminator := firstEntity + 30 + schemaLen
+
+	tests := []struct {
+		name    string
+		blob    []byte
+		wantErr string
+	}{
+		{name: "Version", blob: withByte(0, 2), wantErr: "unsupported Blob version 2"},
+		{name: "OddMetadataLength", blob: withByte(8, byte(metaLen+1)), wantErr: "metadata length is not a multiple of 2"},
+		{name: "UnknownEntity", blob: withByte(firstEntity, 5), wantErr: "unknown entity type 5"},
+		{name: "MissingTerminator", blob: withByte(firstTerminator, 0), wantErr: "missing terminator"},
+		{name: "CorruptSchema", blob: withByte(firstEntity+30, 0x1f), wantErr: "schema entity at offset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBlob(tt.blob)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	blob := testBlob()
+	full, err := ParseBlob(blob)
+	require.NoError(t, err)
+
+	// Every prefix either fails or, when it ends between entities, holds fewer entities
+	for n := 0; n < len(blob); n++ {
+		b, err := ParseBlob(blob[:n])
+		if err == nil {
+			assert.Less(t, len(b.Schemas)+len(b.Events), len(full.Schemas)+len(full.Events), "prefix of %d bytes", n)
+		}
+	}
+
+	payload := compressLiterals(blob)
+	for n := 1; n < len(payload); n++ {
+		_, err := Decode(payload[:n])
+		assert.Error(t, err, "payload prefix of %d bytes", n)
+	}
+}
+
+func TestDecodeRowCorrupt(t *testing.T) {
+	b, err := ParseBlob(testBlob())
+	require.NoError(t, err)
+	def := b.Schemas[0].Def
+	row := logRow("hello", 9, -1, nil)
+
+	cols, err := def.DecodeRow(row[:20])
+	assert.ErrorContains(t, err, `field "env_time"`)
+	assert.Equal(t, []Column{{Name: "env_name", Value: "Log"}}, cols, "columns decoded before the error are kept")
+
+	_, err = def.DecodeRow(append(row, 0))
+	assert.EqualError(t, err, "1 trailing bytes after row")
+
+	// A list that claims more elements than there are bytes left. The tags count follows
+	// env_name (7 bytes), env_time (34), SeverityNumber (4), body (9), retries (8), ratio (8)
+	// and cached (1).
+	long := append([]byte(nil), row...)
+	binary.LittleEndian.PutUint32(long[71:], 1<<30)
+	_, err = def.DecodeRow(long)
+	assert.ErrorContains(t, err, `field "tags"`)
+}
+
+func FuzzDecode(f *testing.F) {
+	blob := testBlob()
+	f.Add(compressLiterals(blob))
+	f.Add(compressLiterals(blob[:len(blob)/2]))
+	f.Add([]byte{0x07, 0x00, 0x00, 0x00, 0x22, 'a', 'b', 0x02, 0x00, 0x10, 'c'})
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		b, err := Decode(payload)
+		if err != nil {
+			return
+		}
+		for _, row := range b.Rows() {
+			_ = row.Err
+		}
+	})
+}
+
+func FuzzParseBlob(f *testing.F) {
+	f.Add(testBlob())
+	f.Fuzz(func(t *testing.T, blob []byte) {
+		b, err := ParseBlob(blob)
+		if err != nil {
+			return
+		}
+		b.Rows()
+	})
+}
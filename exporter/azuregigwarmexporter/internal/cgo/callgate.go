@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrClientClosed is returned by GenevaClient calls made after shutdown has started.
+var ErrClientClosed = errors.New("geneva client is closed")
+
+// callGate reference-counts in-flight FFI calls made with a client handle.
+// Once shutdown starts, new calls are rejected and the release function runs
+// exactly once, after the last in-flight call has exited.
+type callGate struct {
+	mu       sync.Mutex
+	active   int
+	closing  bool
+	released bool
+	drained  chan struct{}
+	release  func()
+}
+
+// newCallGate creates a gate that runs release once all in-flight calls are done
+// after shutdown.
+func newCallGate(release func()) *callGate {
+	return &callGate{
+		drained: make(chan struct{}),
+		release: release,
+	}
+}
+
+// enter registers an in-flight call. It returns ErrClientClosed once shutdown has started.
+// Every successful enter must be paired with exit.
+func (g *callGate) enter() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closing {
+		return ErrClientClosed
+	}
+	g.active++
+	return nil
+}
+
+// exit unregisters an in-flight call and releases the handle if it was the last
+// call of a gate that is shutting down.
+func (g *callGate) exit() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+	if g.closing && g.active == 0 {
+		g.finish()
+	}
+}
+
+// finish runs the release function and signals waiters. Must be called with mu held.
+func (g *callGate) finish() {
+	if g.released {
+		return
+	}
+	g.released = true
+	if g.release != nil {
+		g.release()
+	}
+	close(g.drained)
+}
+
+// shutdown rejects new calls and waits for in-flight calls to exit, up to the context
+// deadline. If the context expires first, the release still happens when the last
+// in-flight call exits, and the context error is returned.
+func (g *callGate) shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	if !g.closing {
+		g.closing = true
+		if g.active == 0 {
+			g.finish()
+		}
+	}
+	inFlight := g.active
+	g.mu.Unlock()
+
+	select {
+	case <-g.drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for %d in-flight geneva calls: %w", inFlight, ctx.Err())
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cgo
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallGateRejectsCallsAfterShutdown(t *testing.T) {
+	var released atomic.Int32
+	g := newCallGate(func() { released.Add(1) })
+
+	require.NoError(t, g.shutdown(context.Background()))
+	assert.ErrorIs(t, g.enter(), ErrClientClosed)
+	assert.Equal(t, int32(1), released.Load())
+
+	// A second shutdown is a no-op.
+	require.NoError(t, g.shutdown(context.Background()))
+	assert.Equal(t, int32(1), released.Load())
+}
+
+func TestCallGateShutdownWaitsForInFlightCalls(t *testing.T) {
+	var released atomic.Int32
+	g := newCallGate(func() { released.Add(1) })
+	require.NoError(t, g.enter())
+
+	done := make(chan error, 1)
+	go func() { done <- g.shutdown(context.Background()) }()
+
+	select {
+	case <-done:
+		t.Fatal("shutdown returned while a call was still in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, int32(0), released.Load())
+	assert.ErrorIs(t, g.enter(), ErrClientClosed)
+
+	g.exit()
+	require.NoError(t, <-done)
+	assert.Equal(t, int32(1), released.Load())
+}
+
+func TestCallGateShutdownDeadline(t *testing.T) {
+	var released atomic.Int32
+	g := newCallGate(func() { released.Add(1) })
+	require.NoError(t, g.enter())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := g.shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(0), released.Load(), "handle must not be freed while a call is in flight")
+
+	// The last in-flight call frees the handle.
+	g.exit()
+	assert.Equal(t, int32(1), released.Load())
+}
+
+// TestCallGateConcurrentShutdown is meant to run with -race: callers read the handle
+// without extra locking, so any access after release is reported as a data race.
+func TestCallGateConcurrentShutdown(t *testing.T) {
+	handle := new(int)
+	*handle = 42
+	g := newCallGate(func() { handle = nil })
+
+	var (
+		wg       sync.WaitGroup
+		calls    atomic.Int64
+		rejected atomic.Int64
+	)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if err := g.enter(); err != nil {
+					rejected.Add(1)
+					return
+				}
+				if *handle != 42 {
+					t.Error("handle used after release")
+				}
+				calls.Add(1)
+				g.exit()
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, g.shutdown(context.Background()))
+	wg.Wait()
+
+	assert.Nil(t, handle)
+	assert.Positive(t, calls.Load())
+	assert.Equal(t, int64(16), rejected.Load())
+}
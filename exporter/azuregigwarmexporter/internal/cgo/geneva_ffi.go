@@ -10,6 +10,7 @@ package cgo
 #cgo LDFLAGS: -L../../geneva_ffi_bridge/target/release -lgeneva_ffi_bridge
 #include "headers/geneva_errors.h"
 #include "headers/geneva_ffi.h"
+#include "headers/geneva_bridge.h"
 #include <stdint.h>
 #include <stdlib.h>
 
@@ -19,6 +20,7 @@ void geneva_set_workload_identity(GenevaConfig* cfg, const char* resource);
 */
 import "C"
 import (
+	"context"
 	"errors"
 	"fmt"
         "log"
@@ -26,9 +28,13 @@ import (
 	"unsafe"
 )
 
-// GenevaClient wraps the Rust Geneva client handle
+// GenevaClient wraps the Rust Geneva client handle.
+//
+// Every FFI call made with the handle is reference-counted so that Shutdown can wait
+// for in-flight encodes and uploads before the handle is freed.
 type GenevaClient struct {
 	handle *C.GenevaClientHandle
+	gate   *callGate
 }
 
 // GenevaConfig represents the Geneva client configuration
@@ -167,7 +173,7 @@ func NewGenevaClient(config GenevaConfig) (*GenevaClient, error) {
 	// Call Rust FFI to create client with error message buffer
 	var handle *C.GenevaClientHandle
 	errBuf := make([]byte, 1024) // Buffer for detailed error messages
-	rc := C.geneva_client_new(
+	rc := C.geneva_tracked_client_new(
 		&cConfig,
 		&handle,
 		(*C.char)(unsafe.Pointer(&errBuf[0])),
@@ -182,7 +188,14 @@ func NewGenevaClient(config GenevaConfig) (*GenevaClient, error) {
 		return nil, mapGenevaError(rc)
 	}
 
-	client := &GenevaClient{handle: handle}
+	// The release closure must not reference client, otherwise the finalizer below
+	// would never run because client would be part of a reference cycle.
+	client := &GenevaClient{
+		handle: handle,
+		gate: newCallGate(func() {
+			C.geneva_tracked_client_free(handle)
+		}),
+	}
 
 	// Set finalizer to ensure cleanup
 	runtime.SetFinalizer(client, (*GenevaClient).Close)
@@ -192,16 +205,17 @@ func NewGenevaClient(config GenevaConfig) (*GenevaClient, error) {
 
 // UploadLogsSync uploads log data to Geneva synchronously (blocking)
 func (c *GenevaClient) UploadLogsSync(data []byte) error {
-	if c.handle == nil {
-		return errors.New("geneva client is closed")
+	if err := c.gate.enter(); err != nil {
+		return err
 	}
+	defer c.gate.exit()
 	if len(data) == 0 {
 		return errors.New("empty log data")
 	}
 
 	var batches *C.EncodedBatchesHandle
 	errBuf := make([]byte, 1024) // Buffer for error messages
-	rc := C.geneva_encode_and_compress_logs(
+	rc := C.geneva_tracked_encode_and_compress_logs(
 		c.handle,
 		(*C.uint8_t)(unsafe.Pointer(&data[0])),
 		C.size_t(len(data)),
@@ -216,12 +230,12 @@ func (c *GenevaClient) UploadLogsSync(data []byte) error {
 		}
 		return mapGenevaError(rc)
 	}
-	defer C.geneva_batches_free(batches)
+	defer C.geneva_tracked_batches_free(batches)
 
 	n := int(C.geneva_batches_len(batches))
 	// Reuse the errBuf for upload errors
 	for i := range n {
-		res := C.geneva_upload_batch_sync(
+		res := C.geneva_tracked_upload_batch_sync(
 			c.handle,
 			batches,
 			C.size_t(i),
@@ -253,25 +267,71 @@ func (b *EncodedBatches) Len() int {
 	return int(C.geneva_batches_len(b.handle))
 }
 
+// BatchInfo describes one encoded batch. Data is a copy of the compressed payload that is
+// uploaded to Geneva.
+type BatchInfo struct {
+	EventName string
+	SchemaIDs string
+	StartTime uint64
+	EndTime   uint64
+	Data      []byte
+}
+
+// Batch returns the payload and metadata of the batch at idx.
+func (b *EncodedBatches) Batch(idx int) (BatchInfo, error) {
+	if b == nil || b.handle == nil {
+		return BatchInfo{}, errors.New("nil batches")
+	}
+	if idx < 0 {
+		return BatchInfo{}, mapGenevaError(genevaErrIndexOutOfRange)
+	}
+	var info C.GenevaBatchInfo
+	if rc := C.geneva_batch_info(b.handle, C.size_t(idx), &info); rc != C.GENEVA_SUCCESS {
+		return BatchInfo{}, mapGenevaError(rc)
+	}
+	return BatchInfo{
+		EventName: C.GoStringN((*C.char)(unsafe.Pointer(info.event_name)), C.int(info.event_name_len)),
+		SchemaIDs: C.GoStringN((*C.char)(unsafe.Pointer(info.schema_ids)), C.int(info.schema_ids_len)),
+		StartTime: uint64(info.start_time),
+		EndTime:   uint64(info.end_time),
+		Data:      C.GoBytes(unsafe.Pointer(info.data), C.int(info.data_len)),
+	}, nil
+}
+
+// Size returns the total size in bytes of the compressed payloads of all batches, without
+// copying them.
+func (b *EncodedBatches) Size() (int, error) {
+	total := 0
+	for i := range b.Len() {
+		var info C.GenevaBatchInfo
+		if rc := C.geneva_batch_info(b.handle, C.size_t(i), &info); rc != C.GENEVA_SUCCESS {
+			return 0, mapGenevaError(rc)
+		}
+		total += int(info.data_len)
+	}
+	return total, nil
+}
+
 // Close frees the underlying batches handle.
 func (b *EncodedBatches) Close() {
 	if b != nil && b.handle != nil {
-		C.geneva_batches_free(b.handle)
+		C.geneva_tracked_batches_free(b.handle)
 		b.handle = nil
 	}
 }
 
 // EncodeAndCompressLogs uses FFI to create compressed batches for upload.
 func (c *GenevaClient) EncodeAndCompressLogs(data []byte) (*EncodedBatches, error) {
-	if c.handle == nil {
-		return nil, errors.New("geneva client is closed")
+	if err := c.gate.enter(); err != nil {
+		return nil, err
 	}
+	defer c.gate.exit()
 	if len(data) == 0 {
 		return nil, errors.New("empty log data")
 	}
 	var batches *C.EncodedBatchesHandle
 	errBuf := make([]byte, 1024)
-	rc := C.geneva_encode_and_compress_logs(
+	rc := C.geneva_tracked_encode_and_compress_logs(
 		c.handle,
 		(*C.uint8_t)(unsafe.Pointer(&data[0])),
 		C.size_t(len(data)),
@@ -291,15 +351,16 @@ func (c *GenevaClient) EncodeAndCompressLogs(data []byte) (*EncodedBatches, erro
 
 // EncodeAndCompressSpans uses FFI to create compressed span batches for upload.
 func (c *GenevaClient) EncodeAndCompressSpans(data []byte) (*EncodedBatches, error) {
-	if c.handle == nil {
-		return nil, errors.New("geneva client is closed")
+	if err := c.gate.enter(); err != nil {
+		return nil, err
 	}
+	defer c.gate.exit()
 	if len(data) == 0 {
 		return nil, errors.New("empty span data")
 	}
 	var batches *C.EncodedBatchesHandle
 	errBuf := make([]byte, 1024)
-	rc := C.geneva_encode_and_compress_spans(
+	rc := C.geneva_tracked_encode_and_compress_spans(
 		c.handle,
 		(*C.uint8_t)(unsafe.Pointer(&data[0])),
 		C.size_t(len(data)),
@@ -317,16 +378,53 @@ func (c *GenevaClient) EncodeAndCompressSpans(data []byte) (*EncodedBatches, err
 	return &EncodedBatches{handle: batches}, nil
 }
 
+// EncodeAndCompressSpansAs is EncodeAndCompressSpans for spans routed to a Geneva event:
+// the spans are written to eventName instead of the encoder's fixed Span event.
+func (c *GenevaClient) EncodeAndCompressSpansAs(data []byte, eventName string) (*EncodedBatches, error) {
+	if err := c.gate.enter(); err != nil {
+		return nil, err
+	}
+	defer c.gate.exit()
+	if len(data) == 0 {
+		return nil, errors.New("empty span data")
+	}
+	if eventName == "" {
+		return nil, errors.New("empty event name")
+	}
+	name := []byte(eventName)
+	var batches *C.EncodedBatchesHandle
+	errBuf := make([]byte, 1024)
+	rc := C.geneva_tracked_encode_and_compress_spans_as(
+		c.handle,
+		(*C.uint8_t)(unsafe.Pointer(&data[0])),
+		C.size_t(len(data)),
+		(*C.uint8_t)(unsafe.Pointer(&name[0])),
+		C.size_t(len(name)),
+		&batches,
+		(*C.char)(unsafe.Pointer(&errBuf[0])),
+		C.size_t(len(errBuf)),
+	)
+	if rc != C.GENEVA_SUCCESS {
+		errMsg := C.GoString((*C.char)(unsafe.Pointer(&errBuf[0])))
+		if errMsg != "" {
+			return nil, fmt.Errorf("%w: %s", mapGenevaError(rc), errMsg)
+		}
+		return nil, mapGenevaError(rc)
+	}
+	return &EncodedBatches{handle: batches}, nil
+}
+
 // UploadBatch uploads a single batch index synchronously.
 func (c *GenevaClient) UploadBatch(b *EncodedBatches, idx int) error {
-	if c.handle == nil {
-		return errors.New("geneva client is closed")
+	if err := c.gate.enter(); err != nil {
+		return err
 	}
+	defer c.gate.exit()
 	if b == nil || b.handle == nil {
 		return errors.New("nil batches")
 	}
 	errBuf := make([]byte, 1024)
-	res := C.geneva_upload_batch_sync(
+	res := C.geneva_tracked_upload_batch_sync(
 		c.handle,
 		b.handle,
 		C.size_t(idx),
@@ -401,11 +499,41 @@ func mapGenevaError(result C.GenevaError) error {
 	}
 }
 
-// Close frees the Geneva client resources
-func (c *GenevaClient) Close() {
-	if c.handle != nil {
-		C.geneva_client_free(c.handle)
-		c.handle = nil
-		runtime.SetFinalizer(c, nil)
+// Shutdown stops accepting new calls, waits for in-flight calls to finish and then
+// frees the Geneva client resources. If ctx expires before all in-flight calls have
+// finished, the context error is returned and the handle is freed as soon as the
+// last in-flight call returns.
+func (c *GenevaClient) Shutdown(ctx context.Context) error {
+	runtime.SetFinalizer(c, nil)
+	return c.gate.shutdown(ctx)
+}
+
+// Stats is a snapshot of the process-wide state of the Rust bridge.
+type Stats struct {
+	// LiveClients is the number of client handles that have not been freed.
+	LiveClients int64
+	// LiveBatches is the number of encoded batches handles that have not been closed.
+	LiveBatches int64
+	// BatchBytes is the number of bytes held by the batches of live batches handles.
+	BatchBytes int64
+	// ClientsUploaded is the number of live clients that have uploaded at least one batch
+	// successfully.
+	ClientsUploaded int64
+}
+
+// BridgeStats returns the current Stats of the Rust bridge.
+func BridgeStats() Stats {
+	var stats C.GenevaStats
+	C.geneva_stats(&stats)
+	return Stats{
+		LiveClients:     int64(stats.live_clients),
+		LiveBatches:     int64(stats.live_batches),
+		BatchBytes:      int64(stats.batch_bytes),
+		ClientsUploaded: int64(stats.clients_uploaded),
 	}
 }
+
+// Close frees the Geneva client resources, waiting for any in-flight calls to finish.
+func (c *GenevaClient) Close() {
+	_ = c.Shutdown(context.Background())
+}
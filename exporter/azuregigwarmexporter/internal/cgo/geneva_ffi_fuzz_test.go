@@ -0,0 +1,269 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build cgo
+
+package cgo
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+// The fuzz targets feed arbitrary bytes through the cgo encoders. Every input must either
+// fail with an error or return readable batches, and must leave no batches handle and no
+// in-flight call behind. Run them with, e.g.
+//
+//	go test -run '^$' -fuzz FuzzEncodeAndCompressLogs -fuzztime 5m ./internal/cgo
+
+func FuzzEncodeAndCompressLogs(f *testing.F) {
+	client := newFuzzClient(f)
+	for _, seed := range fuzzSeeds(f, marshalLogs(f, realisticLogs())) {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkEncode(t, client, data, client.EncodeAndCompressLogs)
+	})
+}
+
+func FuzzEncodeAndCompressSpans(f *testing.F) {
+	client := newFuzzClient(f)
+	for _, seed := range fuzzSeeds(f, marshalTraces(f, realisticTraces())) {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkEncode(t, client, data, client.EncodeAndCompressSpans)
+	})
+}
+
+// checkEncode runs encode on data and asserts its contract.
+func checkEncode(t *testing.T, client *GenevaClient, data []byte, encode func([]byte) (*EncodedBatches, error)) {
+	before := BridgeStats()
+
+	batches, err := encode(data)
+	if err != nil {
+		require.Nil(t, batches, "batches returned together with error %v", err)
+	} else {
+		require.NotNil(t, batches, "neither batches nor an error were returned")
+		for i := 0; i < batches.Len(); i++ {
+			_, err := batches.Batch(i)
+			require.NoError(t, err, "batch %d of %d", i, batches.Len())
+		}
+		_, err = batches.Batch(batches.Len())
+		require.Error(t, err, "batch index past the end")
+		batches.Close()
+	}
+
+	require.Equal(t, before, BridgeStats(), "handle leaked")
+	client.gate.mu.Lock()
+	active := client.gate.active
+	client.gate.mu.Unlock()
+	require.Zero(t, active, "in-flight call leaked")
+}
+
+func newFuzzClient(f *testing.F) *GenevaClient {
+	client, err := NewGenevaClient(GenevaConfig{
+		Endpoint:           "http://127.0.0.1:1",
+		Environment:        "fuzz",
+		Account:            "fuzz",
+		Namespace:          "fuzz",
+		Region:             "local",
+		ConfigMajorVersion: 1,
+		Tenant:             "fuzz-tenant",
+		RoleName:           "fuzz-role",
+		RoleInstance:       "fuzz-instance",
+	})
+	require.NoError(f, err)
+	f.Cleanup(client.Close)
+	return client
+}
+
+// fuzzSeeds derives the seed corpus from a realistic export request: the request itself,
+// truncations and bit flips of it, and hand-written adversarial messages.
+func fuzzSeeds(f *testing.F, realistic []byte) [][]byte {
+	seeds := [][]byte{
+		realistic,
+		realistic[:len(realistic)/2],
+		realistic[:len(realistic)-1],
+		{},
+		{0x00},
+	}
+	for _, pos := range []int{0, 1, len(realistic) / 3, len(realistic) - 1} {
+		flipped := append([]byte(nil), realistic...)
+		flipped[pos] ^= 0xff
+		seeds = append(seeds, flipped)
+	}
+
+	// A length prefix far past the end of the input.
+	seeds = append(seeds, binary.AppendUvarint([]byte{protoTag(1, wireBytes)}, 1<<40))
+	// The resource field with the varint wire type instead of a message.
+	seeds = append(seeds, []byte{protoTag(1, wireVarint), 0x01})
+	// A varint that never terminates.
+	seeds = append(seeds, []byte{protoTag(1, wireVarint), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	// Field number zero, which is invalid.
+	seeds = append(seeds, []byte{0x02, 0x00})
+	// A deprecated group wire type.
+	seeds = append(seeds, []byte{protoTag(1, 3), protoTag(1, 4)})
+	// A resource element of 1000 nested empty messages.
+	var nested []byte
+	for i := 0; i < 1000; i++ {
+		nested = appendBytesField(nil, 1, nested)
+	}
+	seeds = append(seeds, appendBytesField(nil, 1, nested))
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	// Deeply nested bodies.
+	body := records.AppendEmpty().Body().SetEmptyMap()
+	for i := 0; i < 200; i++ {
+		body = body.PutEmptyMap("n")
+	}
+	// Invalid UTF-8 in strings.
+	lr := records.AppendEmpty()
+	lr.Body().SetStr("\xff\xfe\xfd")
+	lr.Attributes().PutStr("\xc3\x28", "\xed\xa0\x80")
+	lr.SetSeverityText("\x80")
+	// Extreme values.
+	lr = records.AppendEmpty()
+	lr.SetTimestamp(pcommon.Timestamp(^uint64(0)))
+	lr.SetSeverityNumber(plog.SeverityNumber(-1))
+	lr.Attributes().PutDouble("nan", math.NaN())
+	lr.Attributes().PutStr("", "empty key")
+	lr.Attributes().PutDouble("inf", math.Inf(-1))
+	// Many empty records.
+	for i := 0; i < 5000; i++ {
+		records.AppendEmpty()
+	}
+	seeds = append(seeds, marshalLogs(f, ld))
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	span := spans.AppendEmpty()
+	span.SetName("\xff\xfe")
+	span.SetKind(ptrace.SpanKind(99))
+	span.SetStartTimestamp(pcommon.Timestamp(^uint64(0)))
+	span.SetEndTimestamp(1)
+	span.Status().SetCode(ptrace.StatusCode(-7))
+	for i := 0; i < 5000; i++ {
+		spans.AppendEmpty()
+	}
+	seeds = append(seeds, marshalTraces(f, td))
+
+	return seeds
+}
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func protoTag(field, wireType byte) byte {
+	return field<<3 | wireType
+}
+
+func appendBytesField(b []byte, field byte, value []byte) []byte {
+	b = append(b, protoTag(field, wireBytes))
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+func marshalLogs(f *testing.F, ld plog.Logs) []byte {
+	data, err := plogotlp.NewExportRequestFromLogs(ld).MarshalProto()
+	require.NoError(f, err)
+	return data
+}
+
+func marshalTraces(f *testing.F, td ptrace.Traces) []byte {
+	data, err := ptraceotlp.NewExportRequestFromTraces(td).MarshalProto()
+	require.NoError(f, err)
+	return data
+}
+
+var fuzzTime = time.Date(2026, 3, 14, 15, 9, 26, 535897900, time.UTC)
+
+// realisticLogs returns logs shaped like a typical application export: two resources,
+// every attribute type, structured and plain bodies, and trace context.
+func realisticLogs() plog.Logs {
+	ld := plog.NewLogs()
+	for r := 0; r < 2; r++ {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "checkout-"+strconv.Itoa(r))
+		rl.Resource().Attributes().PutStr("host.name", "node-1")
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName("app")
+		sl.Scope().SetVersion("1.2.3")
+		for i := 0; i < 5; i++ {
+			lr := sl.LogRecords().AppendEmpty()
+			lr.SetTimestamp(pcommon.NewTimestampFromTime(fuzzTime.Add(time.Duration(i) * time.Millisecond)))
+			lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(fuzzTime.Add(time.Second)))
+			lr.SetSeverityNumber(plog.SeverityNumberWarn)
+			lr.SetSeverityText("WARN")
+			lr.SetTraceID(pcommon.TraceID{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, byte(i)})
+			lr.SetSpanID(pcommon.SpanID{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, byte(i)})
+			lr.SetEventName("CheckoutEvent")
+			if i%2 == 0 {
+				lr.Body().SetStr("payment declined for order " + strconv.Itoa(i))
+			} else {
+				_ = lr.Body().SetEmptyMap().FromRaw(map[string]any{"order": int64(i), "items": []any{"a", "b"}})
+			}
+			lr.Attributes().PutStr("user.id", "u-"+strconv.Itoa(i))
+			lr.Attributes().PutInt("http.response.status_code", 402)
+			lr.Attributes().PutDouble("duration_ms", 12.5)
+			lr.Attributes().PutBool("retry", i > 2)
+			lr.Attributes().PutEmptyBytes("raw").FromRaw([]byte{0x00, 0xff})
+		}
+	}
+	return ld
+}
+
+// realisticTraces returns a small trace with a server root span, client children, events,
+// links and statuses.
+func realisticTraces() ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("app")
+	traceID := pcommon.TraceID{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0c}
+	root := ss.Spans().AppendEmpty()
+	root.SetTraceID(traceID)
+	root.SetSpanID(pcommon.SpanID{1})
+	root.SetName("POST /checkout")
+	root.SetKind(ptrace.SpanKindServer)
+	root.SetStartTimestamp(pcommon.NewTimestampFromTime(fuzzTime))
+	root.SetEndTimestamp(pcommon.NewTimestampFromTime(fuzzTime.Add(120 * time.Millisecond)))
+	root.Attributes().PutStr("http.request.method", "POST")
+	root.Attributes().PutInt("http.response.status_code", 500)
+	root.Status().SetCode(ptrace.StatusCodeError)
+	root.Status().SetMessage("payment service unavailable")
+	for i := 0; i < 4; i++ {
+		child := ss.Spans().AppendEmpty()
+		child.SetTraceID(traceID)
+		child.SetSpanID(pcommon.SpanID{2, byte(i)})
+		child.SetParentSpanID(root.SpanID())
+		child.SetName("GET /payment/" + strings.Repeat("x", i))
+		child.SetKind(ptrace.SpanKindClient)
+		child.SetStartTimestamp(pcommon.NewTimestampFromTime(fuzzTime.Add(time.Duration(i) * time.Millisecond)))
+		child.SetEndTimestamp(pcommon.NewTimestampFromTime(fuzzTime.Add(time.Duration(i+10) * time.Millisecond)))
+		event := child.Events().AppendEmpty()
+		event.SetName("exception")
+		event.SetTimestamp(child.EndTimestamp())
+		event.Attributes().PutStr("exception.message", "connection refused")
+		link := child.Links().AppendEmpty()
+		link.SetTraceID(pcommon.TraceID{9})
+		link.SetSpanID(pcommon.SpanID{9})
+		link.Attributes().PutBool("sampled", true)
+	}
+	return td
+}
@@ -0,0 +1,93 @@
+#ifndef GENEVA_BRIDGE_H
+#define GENEVA_BRIDGE_H
+
+#include <stdint.h>
+#include <stdlib.h>
+#include "geneva_errors.h"
+#include "geneva_ffi.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+/* Functions implemented by geneva_ffi_bridge on top of geneva-uploader-ffi. */
+
+/* Borrowed view of one encoded batch.
+   All pointers stay valid until the batches handle is freed with geneva_batches_free();
+   callers must copy anything they keep. Strings are not NUL-terminated. */
+typedef struct {
+    const uint8_t* data;         /* Compressed payload, as uploaded */
+    size_t data_len;
+    const uint8_t* event_name;   /* Geneva event (table) name */
+    size_t event_name_len;
+    const uint8_t* schema_ids;   /* Schema IDs of the rows in the payload */
+    size_t schema_ids_len;
+    uint64_t start_time;         /* Earliest row timestamp */
+    uint64_t end_time;           /* Latest row timestamp */
+} GenevaBatchInfo;
+
+/* Describe the batch at index (must be < geneva_batches_len(batches)).
+   Returns GENEVA_SUCCESS, GENEVA_ERR_NULL_POINTER or GENEVA_ERR_INDEX_OUT_OF_RANGE. */
+GenevaError geneva_batch_info(const EncodedBatchesHandle* batches,
+                              size_t index,
+                              GenevaBatchInfo* out);
+
+/* Tracked variants of the geneva_ffi.h functions that create and free handles.
+   They have the same contracts and additionally maintain the live handle counts
+   reported by geneva_stats(). Handles must be freed with the tracked function
+   matching the one that created them. */
+GenevaError geneva_tracked_client_new(const GenevaConfig* config,
+                                      GenevaClientHandle** out_handle,
+                                      char* err_msg_out,
+                                      size_t err_msg_len);
+void geneva_tracked_client_free(GenevaClientHandle* handle);
+GenevaError geneva_tracked_encode_and_compress_logs(GenevaClientHandle* handle,
+                                                    const uint8_t* data,
+                                                    size_t data_len,
+                                                    EncodedBatchesHandle** out_batches,
+                                                    char* err_msg_out,
+                                                    size_t err_msg_len);
+GenevaError geneva_tracked_encode_and_compress_spans(GenevaClientHandle* handle,
+                                                     const uint8_t* data,
+                                                     size_t data_len,
+                                                     EncodedBatchesHandle** out_batches,
+                                                     char* err_msg_out,
+                                                     size_t err_msg_len);
+void geneva_tracked_batches_free(EncodedBatchesHandle* batches);
+
+/* geneva_tracked_encode_and_compress_spans writing the spans to the event event_name
+   (UTF-8, event_name_len bytes, not NUL-terminated) instead of the fixed Span event.
+   Returns GENEVA_ERR_EMPTY_INPUT for an empty name and GENEVA_INVALID_DATA if the
+   encoded payload cannot be rewritten. Free the batches with geneva_tracked_batches_free(). */
+GenevaError geneva_tracked_encode_and_compress_spans_as(GenevaClientHandle* handle,
+                                                        const uint8_t* data,
+                                                        size_t data_len,
+                                                        const uint8_t* event_name,
+                                                        size_t event_name_len,
+                                                        EncodedBatchesHandle** out_batches,
+                                                        char* err_msg_out,
+                                                        size_t err_msg_len);
+GenevaError geneva_tracked_upload_batch_sync(GenevaClientHandle* handle,
+                                             const EncodedBatchesHandle* batches,
+                                             size_t index,
+                                             char* err_msg_out,
+                                             size_t err_msg_len);
+
+/* Process-wide bridge state. */
+typedef struct {
+    int64_t live_clients;     /* Client handles created and not yet freed */
+    int64_t live_batches;     /* Batches handles created and not yet freed */
+    int64_t batch_bytes;      /* Bytes held by the batches of live batches handles */
+    int64_t clients_uploaded; /* Live clients that have uploaded at least one batch
+                                 successfully */
+} GenevaStats;
+
+/* Snapshot the bridge state into out.
+   Returns GENEVA_SUCCESS or GENEVA_ERR_NULL_POINTER. */
+GenevaError geneva_stats(GenevaStats* out);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif // GENEVA_BRIDGE_H
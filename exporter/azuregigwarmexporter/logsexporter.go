@@ -23,11 +23,20 @@ import (
 
 // logsExporter implements the logs exporter for Azure Geneva Warm (GigWarm) via Rust FFI.
 type logsExporter struct {
-	params    exporter.Settings
-	cfg       *Config
-	client    *cgogeneva.GenevaClient
-	logger    *zap.Logger
-	telemetry *telemetry
+	params     exporter.Settings
+	cfg        *Config
+	client     genevaClient
+	logger     *zap.Logger
+	telemetry  *telemetry
+	router     *eventNameRouter
+	schema     *schemaMapper
+	redactor   *redactor
+	enricher   *enricher
+	correlate  *correlator
+	body       *bodyTransformer
+	timestamps *timestampNormalizer
+	dumper     *batchDumper
+	severity   *severityFilter
 }
 
 // logsExporter no longer needs to implement consumer.Logs or component.Component
@@ -65,22 +74,42 @@ func newLogsExporter(_ context.Context, set exporter.Settings, cfg *Config) (*lo
 		cgoCfg.CertPath = cfg.CertPath
 		cgoCfg.CertPassword = cfg.CertPassword
 	}
-    // Add workload identity resource if needed
-    if cfg.AuthMethod == WorkloadIdentity {
-        cgoCfg.WorkloadIdentityResource = cfg.WorkloadIdentityResource
-    }
+	// Add workload identity resource if needed
+	if cfg.AuthMethod == WorkloadIdentity {
+		cgoCfg.WorkloadIdentityResource = cfg.WorkloadIdentityResource
+	}
+
+	redactor, err := newRedactor(cfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+
+	dumper, err := acquireBatchDumper(cfg.DebugDump)
+	if err != nil {
+		return nil, err
+	}
 
 	client, err := cgogeneva.NewGenevaClient(cgoCfg)
 	if err != nil {
+		dumper.release()
 		return nil, fmt.Errorf("failed to create Geneva FFI client: %w", err)
 	}
 
 	return &logsExporter{
-		params:    set,
-		cfg:       cfg,
-		client:    client,
-		logger:    set.Logger,
-		telemetry: telemetryInst,
+		params:     set,
+		cfg:        cfg,
+		client:     ffiClient{client: client},
+		logger:     set.Logger,
+		telemetry:  telemetryInst,
+		router:     newEventNameRouter(cfg.EventName),
+		schema:     newSchemaMapper(cfg.Schema),
+		redactor:   redactor,
+		enricher:   newEnricher(cfg.Enrichment, cfg.RoleName, cfg.RoleInstance),
+		correlate:  newCorrelator(cfg.Correlation),
+		body:       newBodyTransformer(cfg.BodyMode, cfg.BodyFlatten),
+		timestamps: newTimestampNormalizer(cfg.Timestamps),
+		dumper:     dumper,
+		severity:   newSeverityFilter(cfg.SeverityPolicy),
 	}, nil
 }
 
@@ -93,16 +122,59 @@ func (e *logsExporter) start(_ context.Context, _ component.Host) error {
 		zap.String("namespace", e.cfg.Namespace),
 		zap.String("region", e.cfg.Region),
 	)
-	return nil
+	if e.cfg.DryRun {
+		e.logger.Warn("Dry run is enabled: data is encoded but never uploaded to Geneva Warm")
+	}
+	return e.telemetry.startBridgeStats()
 }
 
 // shutdown is called by the Collector when the exporter is shutting down.
-func (e *logsExporter) shutdown(_ context.Context) error {
+// In-flight encodes and uploads are drained, up to the context deadline, before the
+// Geneva client handle is freed.
+func (e *logsExporter) shutdown(ctx context.Context) error {
 	e.logger.Info("Shutting down AzureGigWarm exporter")
+	// Drain in-flight uploads first, so that their batches can still be dumped and their
+	// bridge statistics are still reported
+	var err error
 	if e.client != nil {
-		e.client.Close()
+		if err = e.client.Shutdown(ctx); err != nil {
+			e.logger.Warn("Geneva client did not drain before shutdown deadline", zap.Error(err))
+		}
+	}
+	e.dumper.release()
+	if err := e.telemetry.stopBridgeStats(); err != nil {
+		e.logger.Warn("Failed to unregister Rust bridge metrics", zap.Error(err))
+	}
+	return err
+}
+
+// applyPolicies applies the record policies to ld in place. It runs once per request,
+// before the request is queued (see policyLogs).
+func (e *logsExporter) applyPolicies(ctx context.Context, ld plog.Logs) {
+	logAttrs := e.getCommonAttributes()
+
+	// Fill in missing timestamps and clamp or drop out-of-range log records
+	if e.timestamps != nil {
+		e.telemetry.recordTimestampActions(ctx, e.timestamps.normalizeLogs(ld), logAttrs...)
+		if ld.LogRecordCount() == 0 {
+			e.logger.Debug("All log records dropped by timestamp policy")
+			return
+		}
+	}
+
+	// Apply the severity policy before any further work is spent on dropped records
+	if e.severity != nil {
+		e.telemetry.recordSeverityDecisions(ctx, e.severity.filterLogs(ld), logAttrs...)
+		if ld.LogRecordCount() == 0 {
+			e.logger.Debug("All log records dropped by severity policy")
+			return
+		}
+	}
+
+	// Redact sensitive data before routing, so that routing and schema mapping only see redacted values
+	if e.redactor != nil {
+		e.telemetry.recordRedactions(ctx, e.redactor.redactLogs(ld), logAttrs...)
 	}
-	return nil
 }
 
 // pushLogs implements the push function for exporterhelper and sends logs via Rust FFI.
@@ -118,6 +190,85 @@ func (e *logsExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
 		zap.Int("log_records_count", logRecordCount),
 		zap.Int("resource_logs", ld.ResourceLogs().Len()))
 
+	// Transform a copy, so that failed chunks are handed back for retry as received and a
+	// retry never maps already mapped records
+	prepared := plog.NewLogs()
+	ld.CopyTo(prepared)
+
+	// Encode structured bodies according to body_mode
+	if e.body != nil {
+		e.body.transformLogs(prepared)
+	}
+
+	// Add role and host metadata to records that do not set it
+	if e.enricher != nil {
+		e.enricher.enrichLogs(prepared)
+	}
+
+	// Route records to Geneva events before encoding
+	if e.router != nil {
+		e.router.routeLogs(prepared)
+	}
+
+	// Map attributes onto the Geneva table schema
+	if e.schema != nil {
+		e.schema.mapLogs(prepared)
+	}
+
+	// Add trace context columns after schema mapping so that they are never dropped
+	if e.correlate != nil {
+		e.correlate.correlateLogs(prepared)
+	}
+
+	// Split oversized requests so that each encoder call stays within the configured limits
+	plan := planLogs(prepared, e.cfg.MaxRecordsPerRequest, e.cfg.MaxRequestBytes)
+	chunks := plan.chunks()
+	if chunks > 1 {
+		e.logger.Debug("Split logs request into chunks",
+			zap.Int("log_records", logRecordCount),
+			zap.Int("chunks", chunks))
+	}
+
+	var total exportStats
+	failed, err := exportLogsChunks(ld, prepared, plan, func(chunk plog.Logs) error {
+		stats, err := e.exportLogsChunk(ctx, chunk, logAttrs)
+		total.add(stats)
+		return err
+	})
+	if err != nil {
+		if failed < chunks {
+			// Only the failed chunks are handed back to exporterhelper for retry
+			e.logger.Warn("Partially failed to export logs to Geneva Warm",
+				zap.Int("failed_chunks", failed),
+				zap.Int("total_chunks", chunks),
+			)
+		}
+		return err
+	}
+
+	if e.cfg.DryRun {
+		e.logger.Info("Dry run: encoded logs without uploading",
+			zap.Int("log_records", total.records),
+			zap.Int("chunks", chunks),
+			zap.Int("batches", total.batches),
+			zap.Int("bytes", total.bytes),
+		)
+		return nil
+	}
+
+	e.logger.Debug("Successfully uploaded logs to Geneva Warm",
+		zap.Int("log_records", logRecordCount),
+		zap.Int("chunks", chunks),
+		zap.Int("batches", total.batches),
+	)
+	return nil
+}
+
+// exportLogsChunk marshals, encodes and uploads one chunk of a logs request. In dry-run
+// mode the chunk is encoded but not uploaded.
+func (e *logsExporter) exportLogsChunk(ctx context.Context, ld plog.Logs, logAttrs []attribute.KeyValue) (exportStats, error) {
+	logRecordCount := ld.LogRecordCount()
+
 	// Marshal to OTLP ExportLogsServiceRequest protobuf bytes
 	req := plogotlp.NewExportRequestFromLogs(ld)
 	data, err := req.MarshalProto()
@@ -127,7 +278,7 @@ func (e *logsExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
 			attribute.String("error", "marshal_failed"),
 			attribute.String("phase", "encoding"))...)
 
-		return fmt.Errorf("failed to marshal logs to protobuf: %w", err)
+		return exportStats{}, fmt.Errorf("failed to marshal logs to protobuf: %w", err)
 	}
 
 	// Encode once, then upload each batch synchronously via FFI.
@@ -139,11 +290,30 @@ func (e *logsExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
 			attribute.String("error", "encoding_failed"),
 			attribute.String("phase", "encoding"))...)
 
-		return fmt.Errorf("failed to encode logs for Geneva Warm: %w", err)
+		return exportStats{}, fmt.Errorf("failed to encode logs for Geneva Warm: %w", err)
 	}
 	defer batches.Close()
 
+	if e.dumper != nil {
+		if err := e.dumper.dumpEncodedBatches("logs", batches, logShapes(ld)); err != nil {
+			e.logger.Warn("Failed to dump encoded batches", zap.Error(err))
+		}
+	}
+
 	n := batches.Len()
+	size, err := batches.Size()
+	if err != nil {
+		e.logger.Warn("Failed to read encoded batch sizes", zap.Error(err))
+	}
+	stats := exportStats{records: logRecordCount, batches: n, bytes: size}
+
+	if e.cfg.DryRun {
+		e.logger.Debug("Dry run: skipping upload of encoded logs",
+			zap.Int("log_records_count", logRecordCount),
+			zap.Int("batches", n),
+			zap.Int("bytes", size))
+		return stats, nil
+	}
 
 	// Upload batches with retry logic
 	if err := e.uploadBatchesWithRetry(ctx, batches, n); err != nil {
@@ -152,25 +322,21 @@ func (e *logsExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
 			attribute.String("error", "upload_failed"),
 			attribute.String("phase", "upload"))...)
 
-		return err
+		return exportStats{}, err
 	}
 
-	// Record success - metrics recorded only once per successful log export
+	// Record success - metrics recorded only once per successfully exported chunk
 	e.telemetry.recordLogsExported(ctx, int64(logRecordCount), logAttrs...)
 
 	e.logger.Debug("Recording logs exported",
 		zap.Int("log_records_count", logRecordCount),
 		zap.Int("resource_logs", ld.ResourceLogs().Len()))
 
-	e.logger.Debug("Successfully uploaded logs to Geneva Warm",
-		zap.Int("log_records", logRecordCount),
-		zap.Int("batches", n),
-	)
-	return nil
+	return stats, nil
 }
 
 // uploadBatchesWithRetry uploads batches concurrently and retries failed batches
-func (e *logsExporter) uploadBatchesWithRetry(ctx context.Context, batches *cgogeneva.EncodedBatches, n int) error {
+func (e *logsExporter) uploadBatchesWithRetry(ctx context.Context, batches encodedBatches, n int) error {
 	type batchResult struct {
 		index int
 		err   error
@@ -213,7 +379,7 @@ func (e *logsExporter) uploadBatchesWithRetry(ctx context.Context, batches *cgog
 }
 
 // uploadBatchWithRetry uploads a single batch with exponential backoff retry
-func (e *logsExporter) uploadBatchWithRetry(ctx context.Context, batches *cgogeneva.EncodedBatches, index int) error {
+func (e *logsExporter) uploadBatchWithRetry(ctx context.Context, batches encodedBatches, index int) error {
 	// Use common attributes for batch metrics (basic exporter attributes without log-specific data)
 	batchAttrs := e.getCommonAttributes()
 
@@ -251,6 +417,8 @@ func (e *logsExporter) uploadBatchWithRetry(ctx context.Context, batches *cgogen
 
 	var lastErr error
 	backoff := initialInterval
+	attempts := 0
+	failureReason := "max_retries_exceeded"
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		// Check context cancellation
@@ -261,6 +429,7 @@ func (e *logsExporter) uploadBatchWithRetry(ctx context.Context, batches *cgogen
 		}
 
 		// Attempt upload
+		attempts++
 		err := e.client.UploadBatch(batches, index)
 		if err == nil {
 			// Success
@@ -291,6 +460,17 @@ func (e *logsExporter) uploadBatchWithRetry(ctx context.Context, batches *cgogen
 			break
 		}
 
+		// Stop retrying if the export deadline expires before the next attempt could start
+		if !hasTimeForRetry(ctx, backoff) {
+			e.logger.Warn("Remaining export deadline too short for another batch upload attempt",
+				zap.Int("batch_index", index),
+				zap.Int("attempts", attempts),
+				zap.Duration("backoff", backoff),
+			)
+			failureReason = "deadline_exceeded"
+			break
+		}
+
 		// Sleep with backoff
 		select {
 		case <-ctx.Done():
@@ -310,17 +490,18 @@ func (e *logsExporter) uploadBatchWithRetry(ctx context.Context, batches *cgogen
 
 	e.logger.Error("Failed to upload batch after all retries",
 		zap.Int("batch_index", index),
-		zap.Int("attempts", maxRetries+1),
+		zap.Int("attempts", attempts),
+		zap.String("reason", failureReason),
 		zap.Error(lastErr),
 	)
 
 	// Record batch failure
 	e.telemetry.recordBatchExportError(ctx, append(batchAttrs,
-		attribute.String("error", "max_retries_exceeded"),
+		attribute.String("error", failureReason),
 		attribute.Bool("retry_enabled", true),
-		attribute.Int("attempts", maxRetries+1))...)
+		attribute.Int("attempts", attempts))...)
 
-	return fmt.Errorf("failed to upload logs batch %d after %d attempts: %w", index, maxRetries+1, lastErr)
+	return fmt.Errorf("failed to upload logs batch %d after %d attempts: %w", index, attempts, lastErr)
 }
 
 // getCommonAttributes returns the common telemetry attributes for this exporter instance
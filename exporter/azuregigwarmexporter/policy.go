@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// policyLogs applies the record policies of the logs exporter to every request once,
+// before it is queued. exporterhelper retries a failed request by pushing it again, so
+// policies that drop, rewrite or count records must not run in pushLogs: a retry would
+// sample the survivors again, hash already hashed values and count everything twice.
+type policyLogs struct {
+	exporter.Logs
+	apply func(context.Context, plog.Logs)
+}
+
+// ConsumeLogs applies the policies to ld in place and passes the result on. Requests
+// whose records were all dropped are not queued.
+func (p *policyLogs) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	p.apply(ctx, ld)
+	if ld.LogRecordCount() == 0 {
+		return nil
+	}
+	return p.Logs.ConsumeLogs(ctx, ld)
+}
+
+// policyTraces is the traces counterpart of policyLogs.
+type policyTraces struct {
+	exporter.Traces
+	apply func(context.Context, ptrace.Traces)
+}
+
+// ConsumeTraces applies the policies to td in place and passes the result on. Requests
+// whose spans were all dropped are not queued.
+func (p *policyTraces) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	p.apply(ctx, td)
+	if td.SpanCount() == 0 {
+		return nil
+	}
+	return p.Traces.ConsumeTraces(ctx, td)
+}
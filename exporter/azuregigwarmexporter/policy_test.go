@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/otel/metric/noop"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+func newPolicyTestSettings() exporter.Settings {
+	return exporter.Settings{
+		ID: component.NewID(Type),
+		TelemetrySettings: component.TelemetrySettings{
+			Logger:         zap.NewNop(),
+			MeterProvider:  noop.NewMeterProvider(),
+			TracerProvider: tracenoop.NewTracerProvider(),
+		},
+	}
+}
+
+func TestPolicyLogsAppliesOncePerRequest(t *testing.T) {
+	r := newTestRedactor(t, RedactionModeHash)
+	var redactions int64
+	apply := func(_ context.Context, ld plog.Logs) {
+		redactions += r.redactLogs(ld)["email"]
+	}
+
+	// The first push fails, so exporterhelper retries the request
+	var pushed []string
+	push := func(_ context.Context, ld plog.Logs) error {
+		v, _ := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().Get("user")
+		pushed = append(pushed, v.Str())
+		if len(pushed) == 1 {
+			return errors.New("upload failed")
+		}
+		return nil
+	}
+
+	retry := configretry.NewDefaultBackOffConfig()
+	retry.InitialInterval = time.Millisecond
+	queue := exporterhelper.NewDefaultQueueConfig()
+	queue.Enabled = false
+	logs, err := exporterhelper.NewLogs(context.Background(), newPolicyTestSettings(), &Config{}, push,
+		exporterhelper.WithRetry(retry),
+		exporterhelper.WithQueue(queue))
+	require.NoError(t, err)
+	exp := &policyLogs{Logs: logs, apply: apply}
+	require.NoError(t, exp.Start(context.Background(), nil))
+	defer func() { require.NoError(t, exp.Shutdown(context.Background())) }()
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().
+		Attributes().PutStr("user", "grace@example.com")
+	require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+
+	require.Len(t, pushed, 2)
+	assert.Regexp(t, `^sha256:[0-9a-f]{16}$`, pushed[0])
+	assert.Equal(t, pushed[0], pushed[1], "a retry pushes the value hashed once")
+	assert.Equal(t, int64(1), redactions)
+}
+
+func TestPolicyLogsDropsEmptyRequests(t *testing.T) {
+	var pushes int
+	logs, err := exporterhelper.NewLogs(context.Background(), newPolicyTestSettings(), &Config{},
+		func(context.Context, plog.Logs) error {
+			pushes++
+			return nil
+		})
+	require.NoError(t, err)
+	exp := &policyLogs{Logs: logs, apply: func(_ context.Context, ld plog.Logs) {
+		ld.ResourceLogs().RemoveIf(func(plog.ResourceLogs) bool { return true })
+	}}
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+	assert.Zero(t, pushes)
+}
@@ -0,0 +1,275 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// RedactionModeMask replaces redacted text with a fixed mask.
+	RedactionModeMask = "mask"
+	// RedactionModeHash replaces redacted text with a salted SHA-256 hash, so equal values
+	// can still be correlated without exposing them.
+	RedactionModeHash = "hash"
+
+	defaultRedactionMask = "[REDACTED]"
+
+	// redactionHashLength is the number of hex characters of the hash that are kept.
+	redactionHashLength = 16
+)
+
+// RedactionConfig configures redaction of sensitive data before upload. Redaction applies
+// to log bodies, resource / log record / span attribute values and span and span event names.
+type RedactionConfig struct {
+	// Rules is the list of redaction rules, applied in order.
+	Rules []RedactionRule `mapstructure:"rules"`
+	// Mode is the default redaction mode: "mask" or "hash" (default: "mask").
+	Mode string `mapstructure:"mode"`
+	// Mask replaces redacted text in "mask" mode (default: "[REDACTED]").
+	Mask string `mapstructure:"mask"`
+	// HashSalt is prepended to values before hashing in "hash" mode.
+	HashSalt string `mapstructure:"hash_salt"`
+}
+
+// RedactionRule matches sensitive data either by value pattern or by attribute key.
+type RedactionRule struct {
+	// Name identifies the rule in telemetry.
+	Name string `mapstructure:"name"`
+	// Pattern is a regular expression; every match in a string value is redacted.
+	Pattern string `mapstructure:"pattern"`
+	// AttributeKey is a regular expression; the whole value of every attribute whose key
+	// matches is redacted.
+	AttributeKey string `mapstructure:"attribute_key"`
+	// Mode overrides RedactionConfig.Mode for this rule.
+	Mode string `mapstructure:"mode"`
+}
+
+// Validate checks if the redaction configuration is valid.
+func (c *RedactionConfig) Validate() error {
+	if err := validateRedactionMode(c.Mode); err != nil {
+		return err
+	}
+	names := make(map[string]struct{}, len(c.Rules))
+	for i, rule := range c.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf(`rules[%d]: requires a non-empty "name"`, i)
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf(`rules[%d]: duplicate rule name %q`, i, rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+		if (rule.Pattern == "") == (rule.AttributeKey == "") {
+			return fmt.Errorf(`rules[%d]: exactly one of "pattern" or "attribute_key" must be set`, i)
+		}
+		if rule.Pattern != "" {
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				return fmt.Errorf(`rules[%d]: invalid pattern: %w`, i, err)
+			}
+		}
+		if rule.AttributeKey != "" {
+			if _, err := regexp.Compile(rule.AttributeKey); err != nil {
+				return fmt.Errorf(`rules[%d]: invalid attribute_key: %w`, i, err)
+			}
+		}
+		if err := validateRedactionMode(rule.Mode); err != nil {
+			return fmt.Errorf(`rules[%d]: %w`, i, err)
+		}
+	}
+	return nil
+}
+
+func validateRedactionMode(mode string) error {
+	switch mode {
+	case "", RedactionModeMask, RedactionModeHash:
+		return nil
+	default:
+		return fmt.Errorf(`invalid mode %q (must be %q or %q)`, mode, RedactionModeMask, RedactionModeHash)
+	}
+}
+
+// redactionCounts holds the number of redactions per rule name.
+type redactionCounts map[string]int64
+
+// redactionRule is a compiled RedactionRule.
+type redactionRule struct {
+	name  string
+	value *regexp.Regexp
+	key   *regexp.Regexp
+	mode  string
+}
+
+// redactor applies redaction rules to pdata in place.
+type redactor struct {
+	valueRules []redactionRule
+	keyRules   []redactionRule
+	mask       string
+	salt       string
+}
+
+// newRedactor compiles cfg, or returns nil if no rules are configured.
+func newRedactor(cfg RedactionConfig) (*redactor, error) {
+	if len(cfg.Rules) == 0 {
+		return nil, nil
+	}
+	mode := cfg.Mode
+	if mode == "" {
+		mode = RedactionModeMask
+	}
+	r := &redactor{mask: cfg.Mask, salt: cfg.HashSalt}
+	if r.mask == "" {
+		r.mask = defaultRedactionMask
+	}
+	for _, rule := range cfg.Rules {
+		compiled := redactionRule{name: rule.Name, mode: rule.Mode}
+		if compiled.mode == "" {
+			compiled.mode = mode
+		}
+		var err error
+		switch {
+		case rule.Pattern != "":
+			if compiled.value, err = regexp.Compile(rule.Pattern); err != nil {
+				return nil, fmt.Errorf("invalid redaction pattern for rule %q: %w", rule.Name, err)
+			}
+			r.valueRules = append(r.valueRules, compiled)
+		case rule.AttributeKey != "":
+			if compiled.key, err = regexp.Compile(rule.AttributeKey); err != nil {
+				return nil, fmt.Errorf("invalid redaction attribute_key for rule %q: %w", rule.Name, err)
+			}
+			r.keyRules = append(r.keyRules, compiled)
+		default:
+			return nil, errors.New("redaction rule requires a pattern or attribute_key")
+		}
+	}
+	return r, nil
+}
+
+// replacement returns the text that replaces s under the given mode.
+func (r *redactor) replacement(mode, s string) string {
+	if mode == RedactionModeHash {
+		sum := sha256.Sum256([]byte(r.salt + s))
+		return "sha256:" + hex.EncodeToString(sum[:])[:redactionHashLength]
+	}
+	return r.mask
+}
+
+// redactString applies the value rules to s.
+func (r *redactor) redactString(s string, counts redactionCounts) string {
+	for i := range r.valueRules {
+		rule := &r.valueRules[i]
+		n := 0
+		s = rule.value.ReplaceAllStringFunc(s, func(match string) string {
+			n++
+			return r.replacement(rule.mode, match)
+		})
+		if n > 0 {
+			counts[rule.name] += int64(n)
+		}
+	}
+	return s
+}
+
+// redactValue applies the value rules to v, recursing into maps and slices.
+func (r *redactor) redactValue(v pcommon.Value, counts redactionCounts) {
+	switch v.Type() {
+	case pcommon.ValueTypeStr:
+		if len(r.valueRules) > 0 {
+			if out := r.redactString(v.Str(), counts); out != v.Str() {
+				v.SetStr(out)
+			}
+		}
+	case pcommon.ValueTypeMap:
+		r.redactMap(v.Map(), counts)
+	case pcommon.ValueTypeSlice:
+		s := v.Slice()
+		for i := 0; i < s.Len(); i++ {
+			r.redactValue(s.At(i), counts)
+		}
+	}
+}
+
+// redactMap applies the key rules and value rules to every entry of m.
+func (r *redactor) redactMap(m pcommon.Map, counts redactionCounts) {
+	m.Range(func(k string, v pcommon.Value) bool {
+		for i := range r.keyRules {
+			rule := &r.keyRules[i]
+			if rule.key.MatchString(k) {
+				v.SetStr(r.replacement(rule.mode, v.AsString()))
+				counts[rule.name]++
+				return true
+			}
+		}
+		r.redactValue(v, counts)
+		return true
+	})
+}
+
+// redactLogs redacts resource attributes, scope attributes, log bodies and log record
+// attributes in ld.
+func (r *redactor) redactLogs(ld plog.Logs) redactionCounts {
+	counts := make(redactionCounts)
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		r.redactMap(rl.Resource().Attributes(), counts)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			r.redactMap(sl.Scope().Attributes(), counts)
+			records := sl.LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				lr := records.At(k)
+				r.redactValue(lr.Body(), counts)
+				r.redactMap(lr.Attributes(), counts)
+			}
+		}
+	}
+	return counts
+}
+
+// redactTraces redacts resource attributes, scope attributes, span and span event names,
+// span status messages, and span, span event and span link attributes in td.
+func (r *redactor) redactTraces(td ptrace.Traces) redactionCounts {
+	counts := make(redactionCounts)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		r.redactMap(rs.Resource().Attributes(), counts)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			r.redactMap(ss.Scope().Attributes(), counts)
+			spans := ss.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if len(r.valueRules) > 0 {
+					if name := r.redactString(span.Name(), counts); name != span.Name() {
+						span.SetName(name)
+					}
+					if msg := r.redactString(span.Status().Message(), counts); msg != span.Status().Message() {
+						span.Status().SetMessage(msg)
+					}
+				}
+				r.redactMap(span.Attributes(), counts)
+				for e := 0; e < span.Events().Len(); e++ {
+					event := span.Events().At(e)
+					if len(r.valueRules) > 0 {
+						if name := r.redactString(event.Name(), counts); name != event.Name() {
+							event.SetName(name)
+						}
+					}
+					r.redactMap(event.Attributes(), counts)
+				}
+				for l := 0; l < span.Links().Len(); l++ {
+					r.redactMap(span.Links().At(l).Attributes(), counts)
+				}
+			}
+		}
+	}
+	return counts
+}
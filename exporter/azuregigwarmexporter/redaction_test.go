@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func newTestRedactor(t *testing.T, mode string) *redactor {
+	r, err := newRedactor(RedactionConfig{
+		Rules: []RedactionRule{
+			{Name: "email", Pattern: `[a-z]+@example\.com`},
+			{Name: "secret", AttributeKey: `(?i)^password$`},
+		},
+		Mode:     mode,
+		HashSalt: "salt",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	return r
+}
+
+func TestRedactLogs(t *testing.T) {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("owner", "alice@example.com")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().Attributes().PutStr("contact", "bob@example.com")
+	lr := sl.LogRecords().AppendEmpty()
+	lr.Body().SetStr("login by carol@example.com")
+	lr.Attributes().PutStr("Password", "hunter2")
+	lr.Attributes().PutEmptySlice("cc").AppendEmpty().SetStr("dave@example.com")
+
+	counts := newTestRedactor(t, RedactionModeMask).redactLogs(ld)
+	assert.Equal(t, redactionCounts{"email": 4, "secret": 1}, counts)
+	assert.Equal(t, map[string]any{"owner": "[REDACTED]"}, rl.Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"contact": "[REDACTED]"}, sl.Scope().Attributes().AsRaw())
+	assert.Equal(t, "login by [REDACTED]", lr.Body().Str())
+	assert.Equal(t, map[string]any{"Password": "[REDACTED]", "cc": []any{"[REDACTED]"}}, lr.Attributes().AsRaw())
+}
+
+func TestRedactTraces(t *testing.T) {
+	newTraces := func() (ptrace.Traces, ptrace.Span) {
+		td := ptrace.NewTraces()
+		ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+		return td, ss.Spans().AppendEmpty()
+	}
+
+	tests := []struct {
+		name  string
+		setup func(td ptrace.Traces, span ptrace.Span)
+		check func(t *testing.T, td ptrace.Traces, span ptrace.Span)
+	}{
+		{
+			name:  "SpanName",
+			setup: func(_ ptrace.Traces, span ptrace.Span) { span.SetName("GET /users/erin@example.com") },
+			check: func(t *testing.T, _ ptrace.Traces, span ptrace.Span) {
+				assert.Equal(t, "GET /users/[REDACTED]", span.Name())
+			},
+		},
+		{
+			name: "StatusMessage",
+			setup: func(_ ptrace.Traces, span ptrace.Span) {
+				span.Status().SetCode(ptrace.StatusCodeError)
+				span.Status().SetMessage("no mailbox for erin@example.com")
+			},
+			check: func(t *testing.T, _ ptrace.Traces, span ptrace.Span) {
+				assert.Equal(t, "no mailbox for [REDACTED]", span.Status().Message())
+			},
+		},
+		{
+			name: "ScopeAttributes",
+			setup: func(td ptrace.Traces, _ ptrace.Span) {
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Scope().Attributes().PutStr("password", "hunter2")
+			},
+			check: func(t *testing.T, td ptrace.Traces, _ ptrace.Span) {
+				assert.Equal(t, map[string]any{"password": "[REDACTED]"},
+					td.ResourceSpans().At(0).ScopeSpans().At(0).Scope().Attributes().AsRaw())
+			},
+		},
+		{
+			name:  "SpanAttributes",
+			setup: func(_ ptrace.Traces, span ptrace.Span) { span.Attributes().PutStr("user", "erin@example.com") },
+			check: func(t *testing.T, _ ptrace.Traces, span ptrace.Span) {
+				assert.Equal(t, map[string]any{"user": "[REDACTED]"}, span.Attributes().AsRaw())
+			},
+		},
+		{
+			name: "EventAttributes",
+			setup: func(_ ptrace.Traces, span ptrace.Span) {
+				span.Events().AppendEmpty().Attributes().PutStr("user", "erin@example.com")
+			},
+			check: func(t *testing.T, _ ptrace.Traces, span ptrace.Span) {
+				assert.Equal(t, map[string]any{"user": "[REDACTED]"}, span.Events().At(0).Attributes().AsRaw())
+			},
+		},
+		{
+			name: "EventName",
+			setup: func(_ ptrace.Traces, span ptrace.Span) {
+				span.Events().AppendEmpty().SetName("login failed for erin@example.com")
+			},
+			check: func(t *testing.T, _ ptrace.Traces, span ptrace.Span) {
+				assert.Equal(t, "login failed for [REDACTED]", span.Events().At(0).Name())
+			},
+		},
+		{
+			name: "LinkAttributes",
+			setup: func(_ ptrace.Traces, span ptrace.Span) {
+				span.Links().AppendEmpty().Attributes().PutStr("user", "erin@example.com")
+			},
+			check: func(t *testing.T, _ ptrace.Traces, span ptrace.Span) {
+				assert.Equal(t, map[string]any{"user": "[REDACTED]"}, span.Links().At(0).Attributes().AsRaw())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td, span := newTraces()
+			tt.setup(td, span)
+			counts := newTestRedactor(t, RedactionModeMask).redactTraces(td)
+			assert.Len(t, counts, 1)
+			tt.check(t, td, span)
+		})
+	}
+}
+
+func TestRedactHashMode(t *testing.T) {
+	r := newTestRedactor(t, RedactionModeHash)
+	ld := plog.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.Attributes().PutStr("a", "frank@example.com")
+	lr.Attributes().PutStr("b", "frank@example.com")
+
+	counts := r.redactLogs(ld)
+	assert.Equal(t, redactionCounts{"email": 2}, counts)
+	a, _ := lr.Attributes().Get("a")
+	b, _ := lr.Attributes().Get("b")
+	assert.Regexp(t, `^sha256:[0-9a-f]{16}$`, a.Str())
+	assert.Equal(t, a.Str(), b.Str(), "equal values hash to the same replacement")
+}
@@ -0,0 +1,27 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"context"
+	"time"
+)
+
+// minUploadAttemptTime is the minimum time budget left after a backoff sleep for
+// another batch upload attempt to be worth starting.
+const minUploadAttemptTime = 100 * time.Millisecond
+
+// hasTimeForRetry reports whether the context deadline (set by the exporter timeout)
+// leaves enough time to sleep for backoff and then make another upload attempt.
+// Contexts without a deadline allow a retry unless they are already done.
+func hasTimeForRetry(ctx context.Context, backoff time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	return time.Until(deadline) >= backoff+minUploadAttemptTime
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasTimeForRetry(t *testing.T) {
+	const backoff = 200 * time.Millisecond
+
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+		want bool
+	}{
+		{
+			name: "NoDeadline",
+			ctx:  func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			want: true,
+		},
+		{
+			name: "DeadlineAfterWindow",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), backoff+minUploadAttemptTime+time.Minute)
+			},
+			want: true,
+		},
+		{
+			name: "DeadlineInsideWindow",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), backoff+minUploadAttemptTime/2)
+			},
+			want: false,
+		},
+		{
+			name: "Cancelled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			assert.Equal(t, tt.want, hasTimeForRetry(ctx, backoff))
+		})
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// UnmappedKeep passes unmapped attributes to the encoder unchanged.
+	UnmappedKeep = "keep"
+	// UnmappedDrop removes unmapped attributes.
+	UnmappedDrop = "drop"
+	// UnmappedProperties collects unmapped attributes into a single JSON string column.
+	UnmappedProperties = "properties"
+
+	// ColumnTypeString converts the attribute value to a string column.
+	ColumnTypeString = "string"
+	// ColumnTypeInt converts the attribute value to a 64-bit integer column.
+	ColumnTypeInt = "int"
+	// ColumnTypeDouble converts the attribute value to a double column.
+	ColumnTypeDouble = "double"
+	// ColumnTypeBool converts the attribute value to a boolean column.
+	ColumnTypeBool = "bool"
+
+	defaultPropertiesColumn = "properties"
+)
+
+// ColumnMapping maps one attribute to a Geneva column.
+type ColumnMapping struct {
+	// Attribute is the OTLP attribute key.
+	Attribute string `mapstructure:"attribute"`
+	// Column is the Geneva column name (default: the attribute key, which must then be a valid column name).
+	Column string `mapstructure:"column"`
+	// Type is the column type: "string", "int", "double" or "bool". When empty, the
+	// attribute value type is kept.
+	Type string `mapstructure:"type"`
+}
+
+// columnName returns the effective column name of the mapping.
+func (m *ColumnMapping) columnName() string {
+	if m.Column != "" {
+		return m.Column
+	}
+	return m.Attribute
+}
+
+// SchemaConfig maps free-form OTLP attributes onto the fixed schema of a Geneva table.
+// It is applied to log record and span attributes before encoding.
+type SchemaConfig struct {
+	// Columns renames and types log record / span attributes.
+	Columns []ColumnMapping `mapstructure:"columns"`
+	// ResourceColumns promotes resource attributes to top-level columns on every record.
+	// A column already set from the record's own attributes takes precedence.
+	ResourceColumns []ColumnMapping `mapstructure:"resource_columns"`
+	// Unmapped selects what happens to attributes not listed in Columns: "keep", "drop"
+	// or "properties" (default: "keep"). The mapping is disabled when no columns are
+	// configured and Unmapped is "keep".
+	Unmapped string `mapstructure:"unmapped"`
+	// PropertiesColumn is the column that receives unmapped attributes as a JSON object
+	// when Unmapped is "properties" (default: "properties").
+	PropertiesColumn string `mapstructure:"properties_column"`
+}
+
+// Validate checks if the schema mapping configuration is valid.
+func (c *SchemaConfig) Validate() error {
+	switch c.Unmapped {
+	case "", UnmappedKeep, UnmappedDrop, UnmappedProperties:
+	default:
+		return fmt.Errorf(`invalid unmapped %q (must be %q, %q or %q)`, c.Unmapped, UnmappedKeep, UnmappedDrop, UnmappedProperties)
+	}
+	if c.PropertiesColumn != "" {
+		if c.Unmapped != UnmappedProperties {
+			return errors.New(`"properties_column" requires unmapped == "properties"`)
+		}
+		if !isValidGenevaName(c.PropertiesColumn) {
+			return fmt.Errorf(`invalid properties_column %q`, c.PropertiesColumn)
+		}
+	}
+
+	columns := make(map[string]struct{})
+	check := func(field string, mappings []ColumnMapping) error {
+		attrs := make(map[string]struct{})
+		for i := range mappings {
+			m := &mappings[i]
+			if m.Attribute == "" {
+				return fmt.Errorf(`%s[%d]: requires a non-empty "attribute"`, field, i)
+			}
+			if _, ok := attrs[m.Attribute]; ok {
+				return fmt.Errorf(`%s[%d]: attribute %q is mapped more than once`, field, i, m.Attribute)
+			}
+			attrs[m.Attribute] = struct{}{}
+			name := m.columnName()
+			if !isValidGenevaName(name) {
+				return fmt.Errorf(`%s[%d]: invalid column name %q`, field, i, name)
+			}
+			if _, ok := columns[name]; ok {
+				return fmt.Errorf(`%s[%d]: column %q is mapped more than once`, field, i, name)
+			}
+			columns[name] = struct{}{}
+			switch m.Type {
+			case "", ColumnTypeString, ColumnTypeInt, ColumnTypeDouble, ColumnTypeBool:
+			default:
+				return fmt.Errorf(`%s[%d]: invalid type %q (must be %q, %q, %q or %q)`, field, i, m.Type,
+					ColumnTypeString, ColumnTypeInt, ColumnTypeDouble, ColumnTypeBool)
+			}
+		}
+		return nil
+	}
+	if err := check("columns", c.Columns); err != nil {
+		return err
+	}
+	if err := check("resource_columns", c.ResourceColumns); err != nil {
+		return err
+	}
+	if c.Unmapped == UnmappedProperties {
+		if _, ok := columns[c.propertiesColumn()]; ok {
+			return fmt.Errorf(`properties column %q collides with a mapped column`, c.propertiesColumn())
+		}
+	}
+
+	// A column must not be renamed again when the mapping is applied to its own output
+	mappedAttrs := make(map[string]struct{}, len(c.Columns))
+	for i := range c.Columns {
+		mappedAttrs[c.Columns[i].Attribute] = struct{}{}
+	}
+	for i := range c.Columns {
+		m := &c.Columns[i]
+		if name := m.columnName(); name != m.Attribute {
+			if _, ok := mappedAttrs[name]; ok {
+				return fmt.Errorf(`columns[%d]: column %q is also a mapped attribute`, i, name)
+			}
+		}
+	}
+	for i := range c.ResourceColumns {
+		name := c.ResourceColumns[i].columnName()
+		if _, ok := mappedAttrs[name]; ok {
+			return fmt.Errorf(`resource_columns[%d]: column %q is also a mapped attribute`, i, name)
+		}
+	}
+	if c.Unmapped == UnmappedProperties {
+		if _, ok := mappedAttrs[c.propertiesColumn()]; ok {
+			return fmt.Errorf(`properties column %q is also a mapped attribute`, c.propertiesColumn())
+		}
+	}
+	return nil
+}
+
+// propertiesColumn returns the effective properties column name.
+func (c *SchemaConfig) propertiesColumn() string {
+	if c.PropertiesColumn != "" {
+		return c.PropertiesColumn
+	}
+	return defaultPropertiesColumn
+}
+
+// schemaMapper applies a SchemaConfig to record attributes.
+type schemaMapper struct {
+	columns          []ColumnMapping
+	resourceColumns  []ColumnMapping
+	mapped           map[string]struct{}
+	targets          map[string]struct{}
+	unmapped         string
+	propertiesColumn string
+}
+
+// newSchemaMapper creates a mapper for cfg, or returns nil if the mapping is a no-op.
+func newSchemaMapper(cfg SchemaConfig) *schemaMapper {
+	unmapped := cfg.Unmapped
+	if unmapped == "" {
+		unmapped = UnmappedKeep
+	}
+	if len(cfg.Columns) == 0 && len(cfg.ResourceColumns) == 0 && unmapped == UnmappedKeep {
+		return nil
+	}
+	m := &schemaMapper{
+		columns:          cfg.Columns,
+		resourceColumns:  cfg.ResourceColumns,
+		mapped:           make(map[string]struct{}, len(cfg.Columns)),
+		targets:          make(map[string]struct{}, len(cfg.Columns)+len(cfg.ResourceColumns)),
+		unmapped:         unmapped,
+		propertiesColumn: cfg.propertiesColumn(),
+	}
+	for _, c := range cfg.Columns {
+		m.mapped[c.Attribute] = struct{}{}
+		m.targets[c.columnName()] = struct{}{}
+	}
+	for _, c := range cfg.ResourceColumns {
+		m.targets[c.columnName()] = struct{}{}
+	}
+	return m
+}
+
+// mapAttributes rewrites attrs according to the schema. Mapped columns are written first,
+// then promoted resource columns, then unmapped attributes, so the result does not depend
+// on attribute iteration order. Values that cannot be converted to the configured column
+// type are treated as unmapped.
+//
+// Attributes that already carry a target column name are kept as columns.
+func (m *schemaMapper) mapAttributes(resourceAttrs, attrs pcommon.Map) {
+	out := pcommon.NewMap()
+	out.EnsureCapacity(attrs.Len() + len(m.resourceColumns))
+	failed := make(map[string]struct{})
+
+	for i := range m.columns {
+		c := &m.columns[i]
+		v, ok := attrs.Get(c.Attribute)
+		if !ok {
+			continue
+		}
+		if !convertColumnValue(v, c.Type, out, c.columnName()) {
+			failed[c.Attribute] = struct{}{}
+		}
+	}
+
+	for i := range m.resourceColumns {
+		c := &m.resourceColumns[i]
+		if _, exists := out.Get(c.columnName()); exists {
+			continue
+		}
+		if v, ok := resourceAttrs.Get(c.Attribute); ok {
+			convertColumnValue(v, c.Type, out, c.columnName())
+		}
+	}
+
+	var properties map[string]any
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		if _, ok := m.mapped[k]; ok {
+			if _, conversionFailed := failed[k]; !conversionFailed {
+				return true
+			}
+		}
+		if _, ok := m.targets[k]; ok {
+			if _, exists := out.Get(k); !exists {
+				v.CopyTo(out.PutEmpty(k))
+			}
+			return true
+		}
+		switch m.unmapped {
+		case UnmappedKeep:
+			if _, exists := out.Get(k); !exists {
+				v.CopyTo(out.PutEmpty(k))
+			}
+		case UnmappedProperties:
+			if properties == nil {
+				properties = make(map[string]any)
+			}
+			properties[k] = v.AsRaw()
+		}
+		return true
+	})
+	if len(properties) > 0 {
+		// encoding/json sorts map keys, so the column content is deterministic
+		if b, err := json.Marshal(properties); err == nil {
+			out.PutStr(m.propertiesColumn, string(b))
+		}
+	}
+
+	out.MoveTo(attrs)
+}
+
+// convertColumnValue writes v converted to typ into dest[column]. It returns false and
+// leaves dest unchanged if the value cannot be converted.
+func convertColumnValue(v pcommon.Value, typ string, dest pcommon.Map, column string) bool {
+	switch typ {
+	case "":
+		v.CopyTo(dest.PutEmpty(column))
+	case ColumnTypeString:
+		dest.PutStr(column, v.AsString())
+	case ColumnTypeInt:
+		switch v.Type() {
+		case pcommon.ValueTypeInt:
+			dest.PutInt(column, v.Int())
+		case pcommon.ValueTypeDouble:
+			if math.IsNaN(v.Double()) || math.IsInf(v.Double(), 0) {
+				return false
+			}
+			dest.PutInt(column, int64(v.Double()))
+		case pcommon.ValueTypeBool:
+			if v.Bool() {
+				dest.PutInt(column, 1)
+			} else {
+				dest.PutInt(column, 0)
+			}
+		case pcommon.ValueTypeStr:
+			i, err := strconv.ParseInt(v.Str(), 10, 64)
+			if err != nil {
+				return false
+			}
+			dest.PutInt(column, i)
+		default:
+			return false
+		}
+	case ColumnTypeDouble:
+		switch v.Type() {
+		case pcommon.ValueTypeDouble:
+			dest.PutDouble(column, v.Double())
+		case pcommon.ValueTypeInt:
+			dest.PutDouble(column, float64(v.Int()))
+		case pcommon.ValueTypeStr:
+			f, err := strconv.ParseFloat(v.Str(), 64)
+			if err != nil {
+				return false
+			}
+			dest.PutDouble(column, f)
+		default:
+			return false
+		}
+	case ColumnTypeBool:
+		switch v.Type() {
+		case pcommon.ValueTypeBool:
+			dest.PutBool(column, v.Bool())
+		case pcommon.ValueTypeInt:
+			dest.PutBool(column, v.Int() != 0)
+		case pcommon.ValueTypeStr:
+			b, err := strconv.ParseBool(v.Str())
+			if err != nil {
+				return false
+			}
+			dest.PutBool(column, b)
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// mapLogs applies the schema to every log record in ld.
+func (m *schemaMapper) mapLogs(ld plog.Logs) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		resourceAttrs := rl.Resource().Attributes()
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				m.mapAttributes(resourceAttrs, records.At(k).Attributes())
+			}
+		}
+	}
+}
+
+// mapTraces applies the schema to every span in td.
+func (m *schemaMapper) mapTraces(td ptrace.Traces) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		resourceAttrs := rs.Resource().Attributes()
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				m.mapAttributes(resourceAttrs, spans.At(k).Attributes())
+			}
+		}
+	}
+}
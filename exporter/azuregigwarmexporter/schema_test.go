@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func newSchemaTestLogs() plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	rl.Resource().Attributes().PutStr("host.name", "vm-1")
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.Attributes().PutStr("http.status_code", "503")
+	lr.Attributes().PutStr("user.id", "u-1")
+	lr.Attributes().PutInt("retries", 2)
+	lr.Attributes().PutStr("latency", "fast")
+	lr.Attributes().PutStr("Service", "from-record")
+	return ld
+}
+
+func TestSchemaMapperMapLogs(t *testing.T) {
+	columns := []ColumnMapping{
+		{Attribute: "http.status_code", Column: "StatusCode", Type: ColumnTypeInt},
+		{Attribute: "user.id", Column: "UserId"},
+		{Attribute: "latency", Column: "Latency", Type: ColumnTypeDouble},
+	}
+	resourceColumns := []ColumnMapping{
+		{Attribute: "service.name", Column: "Service"},
+		{Attribute: "host.name", Column: "Host"},
+	}
+
+	tests := []struct {
+		name     string
+		unmapped string
+		want     map[string]any
+	}{
+		{
+			name:     "Keep",
+			unmapped: UnmappedKeep,
+			want: map[string]any{
+				"StatusCode": int64(503),
+				"UserId":     "u-1",
+				"Service":    "checkout",
+				"Host":       "vm-1",
+				"retries":    int64(2),
+				"latency":    "fast",
+			},
+		},
+		{
+			name:     "Drop",
+			unmapped: UnmappedDrop,
+			want: map[string]any{
+				"StatusCode": int64(503),
+				"UserId":     "u-1",
+				"Service":    "checkout",
+				"Host":       "vm-1",
+			},
+		},
+		{
+			name:     "Properties",
+			unmapped: UnmappedProperties,
+			want: map[string]any{
+				"StatusCode": int64(503),
+				"UserId":     "u-1",
+				"Service":    "checkout",
+				"Host":       "vm-1",
+				"properties": `{"latency":"fast","retries":2}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := SchemaConfig{Columns: columns, ResourceColumns: resourceColumns, Unmapped: tt.unmapped}
+			require.NoError(t, cfg.Validate())
+			m := newSchemaMapper(cfg)
+			require.NotNil(t, m)
+
+			ld := newSchemaTestLogs()
+			m.mapLogs(ld)
+			attrs := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+			assert.Equal(t, tt.want, attrs.AsRaw())
+
+			if tt.unmapped != UnmappedProperties {
+				// Attributes that already carry a column name are kept as columns
+				m.mapLogs(ld)
+				assert.Equal(t, tt.want, attrs.AsRaw())
+			}
+		})
+	}
+}
+
+func TestSchemaMapperUserProperties(t *testing.T) {
+	m := newSchemaMapper(SchemaConfig{Unmapped: UnmappedProperties})
+	attrs := pcommon.NewMap()
+	attrs.PutStr("properties", `{"a":1}`)
+	attrs.PutStr("a", "x")
+
+	// A properties attribute of the record is carried into the column unchanged, not merged
+	m.mapAttributes(pcommon.NewMap(), attrs)
+	assert.Equal(t, map[string]any{"properties": `{"a":"x","properties":"{\"a\":1}"}`}, attrs.AsRaw())
+}
+
+func TestSchemaMapperConversionFailure(t *testing.T) {
+	m := newSchemaMapper(SchemaConfig{
+		Columns:  []ColumnMapping{{Attribute: "code", Column: "Code", Type: ColumnTypeInt}},
+		Unmapped: UnmappedKeep,
+	})
+	attrs := pcommon.NewMap()
+	attrs.PutStr("code", "not-a-number")
+	m.mapAttributes(pcommon.NewMap(), attrs)
+	assert.Equal(t, map[string]any{"code": "not-a-number"}, attrs.AsRaw())
+}
+
+func TestSchemaConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SchemaConfig
+		wantErr string
+	}{
+		{
+			name: "Valid",
+			cfg: SchemaConfig{
+				Columns:  []ColumnMapping{{Attribute: "a", Column: "A"}, {Attribute: "Same"}},
+				Unmapped: UnmappedProperties,
+			},
+		},
+		{
+			name:    "InvalidUnmapped",
+			cfg:     SchemaConfig{Unmapped: "nest"},
+			wantErr: `invalid unmapped "nest"`,
+		},
+		{
+			name:    "DuplicateColumn",
+			cfg:     SchemaConfig{Columns: []ColumnMapping{{Attribute: "a", Column: "X"}, {Attribute: "b", Column: "X"}}},
+			wantErr: `columns[1]: column "X" is mapped more than once`,
+		},
+		{
+			name:    "ColumnIsMappedAttribute",
+			cfg:     SchemaConfig{Columns: []ColumnMapping{{Attribute: "a", Column: "b"}, {Attribute: "b", Column: "C"}}},
+			wantErr: `columns[0]: column "b" is also a mapped attribute`,
+		},
+		{
+			name: "ResourceColumnIsMappedAttribute",
+			cfg: SchemaConfig{
+				Columns:         []ColumnMapping{{Attribute: "Host", Column: "H"}},
+				ResourceColumns: []ColumnMapping{{Attribute: "host.name", Column: "Host"}},
+			},
+			wantErr: `resource_columns[0]: column "Host" is also a mapped attribute`,
+		},
+		{
+			name: "PropertiesColumnIsMappedAttribute",
+			cfg: SchemaConfig{
+				Columns:  []ColumnMapping{{Attribute: "properties", Column: "Props"}},
+				Unmapped: UnmappedProperties,
+			},
+			wantErr: `properties column "properties" is also a mapped attribute`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	// SeverityActionKeep exports all records of a severity level.
+	SeverityActionKeep = "keep"
+	// SeverityActionDrop drops all records of a severity level.
+	SeverityActionDrop = "drop"
+	// SeverityActionSample exports a fraction of the records of a severity level.
+	SeverityActionSample = "sample"
+
+	severityDecisionKept    = "kept"
+	severityDecisionDropped = "dropped"
+)
+
+// severityLevels are the accepted keys of SeverityPolicyConfig.Levels. Each level covers a
+// range of OTLP severity numbers; "unspecified" covers records without a severity number.
+var severityLevels = []string{"unspecified", "trace", "debug", "info", "warn", "error", "fatal"}
+
+// SeverityPolicyConfig configures per-severity filtering and sampling of log records.
+type SeverityPolicyConfig struct {
+	// Levels maps a severity level ("unspecified", "trace", "debug", "info", "warn",
+	// "error" or "fatal") to its policy. Levels that are not listed are kept.
+	Levels map[string]SeverityPolicy `mapstructure:"levels"`
+	// ConsistentByTraceID makes sampling decisions a function of the trace ID, so that all
+	// sampled records of a trace are either kept or dropped together. Records without a
+	// trace ID are sampled randomly.
+	ConsistentByTraceID bool `mapstructure:"consistent_by_trace_id"`
+}
+
+// SeverityPolicy is the policy of one severity level.
+type SeverityPolicy struct {
+	// Action is "keep", "drop" or "sample".
+	Action string `mapstructure:"action"`
+	// SampleRate is the fraction of records kept in "sample" mode, in [0, 1].
+	SampleRate float64 `mapstructure:"sample_rate"`
+}
+
+// Validate checks if the severity policy configuration is valid.
+func (c *SeverityPolicyConfig) Validate() error {
+	for level, policy := range c.Levels {
+		if severityLevelIndex(level) < 0 {
+			return fmt.Errorf(`levels: unknown severity level %q (must be one of %q)`, level, severityLevels)
+		}
+		switch policy.Action {
+		case SeverityActionKeep, SeverityActionDrop:
+			if policy.SampleRate != 0 {
+				return fmt.Errorf(`levels[%q]: "sample_rate" requires action == %q`, level, SeverityActionSample)
+			}
+		case SeverityActionSample:
+			if policy.SampleRate < 0 || policy.SampleRate > 1 {
+				return fmt.Errorf(`levels[%q]: invalid sample_rate %v (must be in [0, 1])`, level, policy.SampleRate)
+			}
+		case "":
+			return fmt.Errorf(`levels[%q]: requires an "action"`, level)
+		default:
+			return fmt.Errorf(`levels[%q]: invalid action %q (must be %q, %q or %q)`, level, policy.Action,
+				SeverityActionKeep, SeverityActionDrop, SeverityActionSample)
+		}
+	}
+	if c.ConsistentByTraceID && len(c.Levels) == 0 {
+		return errors.New(`"consistent_by_trace_id" requires "levels"`)
+	}
+	return nil
+}
+
+// severityLevelIndex returns the index of level in severityLevels, or -1.
+func severityLevelIndex(level string) int {
+	for i, l := range severityLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// severityLevelOf returns the index in severityLevels of the level of a severity number.
+func severityLevelOf(n plog.SeverityNumber) int {
+	if n <= plog.SeverityNumberUnspecified || n > plog.SeverityNumberFatal4 {
+		return 0
+	}
+	// Every level covers four severity numbers, starting at SeverityNumberTrace (1)
+	return 1 + int(n-plog.SeverityNumberTrace)/4
+}
+
+// severityDecisionKey identifies a severity policy counter.
+type severityDecisionKey struct {
+	level    string
+	decision string
+}
+
+// severityDecisions holds the number of kept and dropped records per severity level.
+type severityDecisions map[severityDecisionKey]int64
+
+// severityFilter applies the severity policy to log records.
+type severityFilter struct {
+	// policies is indexed like severityLevels
+	policies   []SeverityPolicy
+	consistent bool
+	random     func() float64
+}
+
+// newSeverityFilter creates a filter for cfg, or returns nil if no levels are configured.
+func newSeverityFilter(cfg SeverityPolicyConfig) *severityFilter {
+	if len(cfg.Levels) == 0 {
+		return nil
+	}
+	f := &severityFilter{
+		policies:   make([]SeverityPolicy, len(severityLevels)),
+		consistent: cfg.ConsistentByTraceID,
+		random:     rand.Float64,
+	}
+	for i, level := range severityLevels {
+		policy, ok := cfg.Levels[level]
+		if !ok {
+			policy = SeverityPolicy{Action: SeverityActionKeep}
+		}
+		f.policies[i] = policy
+	}
+	return f
+}
+
+// keep decides whether a record is exported.
+func (f *severityFilter) keep(lr plog.LogRecord, policy SeverityPolicy) bool {
+	switch policy.Action {
+	case SeverityActionDrop:
+		return false
+	case SeverityActionSample:
+		if f.consistent && !lr.TraceID().IsEmpty() {
+			return traceIDRatio(lr.TraceID()) < policy.SampleRate
+		}
+		return f.random() < policy.SampleRate
+	default:
+		return true
+	}
+}
+
+// filterLogs removes the log records of ld that the policy drops.
+func (f *severityFilter) filterLogs(ld plog.Logs) severityDecisions {
+	decisions := make(severityDecisions)
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			rl.ScopeLogs().At(j).LogRecords().RemoveIf(func(lr plog.LogRecord) bool {
+				level := severityLevelOf(lr.SeverityNumber())
+				if f.keep(lr, f.policies[level]) {
+					decisions[severityDecisionKey{severityLevels[level], severityDecisionKept}]++
+					return false
+				}
+				decisions[severityDecisionKey{severityLevels[level], severityDecisionDropped}]++
+				return true
+			})
+		}
+	}
+	return decisions
+}
+
+// traceIDRatio maps a trace ID to a value in [0, 1) that is stable across collectors.
+func traceIDRatio(traceID [16]byte) float64 {
+	h := fnv.New64a()
+	_, _ = h.Write(traceID[:])
+	// Use the top 53 bits so that the result is exactly representable as a float64
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
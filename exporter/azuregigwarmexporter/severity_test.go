@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestSeverityLevelOf(t *testing.T) {
+	tests := []struct {
+		n    plog.SeverityNumber
+		want string
+	}{
+		{plog.SeverityNumberUnspecified, "unspecified"},
+		{plog.SeverityNumberTrace, "trace"},
+		{plog.SeverityNumberDebug4, "debug"},
+		{plog.SeverityNumberInfo, "info"},
+		{plog.SeverityNumberWarn2, "warn"},
+		{plog.SeverityNumberError4, "error"},
+		{plog.SeverityNumberFatal4, "fatal"},
+		{plog.SeverityNumber(25), "unspecified"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, severityLevels[severityLevelOf(tt.n)], "severity number %d", tt.n)
+	}
+}
+
+func TestSeverityFilter(t *testing.T) {
+	f := newSeverityFilter(SeverityPolicyConfig{Levels: map[string]SeverityPolicy{
+		"debug": {Action: SeverityActionDrop},
+		"info":  {Action: SeverityActionSample, SampleRate: 0.5},
+	}})
+	require.NotNil(t, f)
+	// Alternate between keeping and dropping sampled records
+	draws := []float64{0.1, 0.9}
+	f.random = func() float64 {
+		v := draws[0]
+		draws = append(draws[1:], v)
+		return v
+	}
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, n := range []plog.SeverityNumber{
+		plog.SeverityNumberDebug, plog.SeverityNumberInfo, plog.SeverityNumberInfo2,
+		plog.SeverityNumberInfo3, plog.SeverityNumberError, plog.SeverityNumberUnspecified,
+	} {
+		records.AppendEmpty().SetSeverityNumber(n)
+	}
+
+	decisions := f.filterLogs(ld)
+	assert.Equal(t, severityDecisions{
+		{"debug", severityDecisionDropped}:    1,
+		{"info", severityDecisionKept}:        2,
+		{"info", severityDecisionDropped}:     1,
+		{"error", severityDecisionKept}:       1,
+		{"unspecified", severityDecisionKept}: 1,
+	}, decisions)
+	require.Equal(t, 4, records.Len())
+	assert.Equal(t, plog.SeverityNumberInfo, records.At(0).SeverityNumber())
+	assert.Equal(t, plog.SeverityNumberInfo3, records.At(1).SeverityNumber())
+}
+
+func TestSeverityFilterConsistentByTraceID(t *testing.T) {
+	f := newSeverityFilter(SeverityPolicyConfig{
+		Levels:              map[string]SeverityPolicy{"info": {Action: SeverityActionSample, SampleRate: 0.5}},
+		ConsistentByTraceID: true,
+	})
+	f.random = func() float64 {
+		t.Fatal("records with a trace ID are not sampled randomly")
+		return 0
+	}
+	ratio := traceIDRatio(testTraceID)
+	require.GreaterOrEqual(t, ratio, 0.0)
+	require.Less(t, ratio, 1.0)
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 3; i++ {
+		lr := records.AppendEmpty()
+		lr.SetSeverityNumber(plog.SeverityNumberInfo)
+		lr.SetTraceID(testTraceID)
+	}
+
+	f.filterLogs(ld)
+	if ratio < 0.5 {
+		assert.Equal(t, 3, records.Len(), "all records of a trace are kept together")
+	} else {
+		assert.Zero(t, records.Len(), "all records of a trace are dropped together")
+	}
+}
+
+func TestSeverityPolicyAppliedOncePerRequest(t *testing.T) {
+	f := newSeverityFilter(SeverityPolicyConfig{Levels: map[string]SeverityPolicy{
+		"info": {Action: SeverityActionSample, SampleRate: 0.5},
+	}})
+	var draws int
+	f.random = func() float64 {
+		draws++
+		// Keep the first record and drop the second
+		if draws%2 == 1 {
+			return 0.1
+		}
+		return 0.9
+	}
+	var kept int64
+	apply := func(_ context.Context, ld plog.Logs) {
+		kept += f.filterLogs(ld)[severityDecisionKey{"info", severityDecisionKept}]
+	}
+
+	// The first push fails, so exporterhelper retries the request
+	var pushed []int
+	push := func(_ context.Context, ld plog.Logs) error {
+		pushed = append(pushed, ld.LogRecordCount())
+		if len(pushed) == 1 {
+			return errors.New("upload failed")
+		}
+		return nil
+	}
+
+	retry := configretry.NewDefaultBackOffConfig()
+	retry.InitialInterval = time.Millisecond
+	queue := exporterhelper.NewDefaultQueueConfig()
+	queue.Enabled = false
+	logs, err := exporterhelper.NewLogs(context.Background(), newPolicyTestSettings(), &Config{}, push,
+		exporterhelper.WithRetry(retry),
+		exporterhelper.WithQueue(queue))
+	require.NoError(t, err)
+	exp := &policyLogs{Logs: logs, apply: apply}
+	require.NoError(t, exp.Start(context.Background(), nil))
+	defer func() { require.NoError(t, exp.Shutdown(context.Background())) }()
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().SetSeverityNumber(plog.SeverityNumberInfo)
+	records.AppendEmpty().SetSeverityNumber(plog.SeverityNumberInfo)
+	require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+
+	assert.Equal(t, []int{1, 1}, pushed, "a retry pushes the sampled request again without resampling")
+	assert.Equal(t, 2, draws)
+	assert.Equal(t, int64(1), kept)
+}
+
+func TestSeverityPolicyConfigValidate(t *testing.T) {
+	assert.Nil(t, newSeverityFilter(SeverityPolicyConfig{}))
+
+	tests := []struct {
+		name    string
+		cfg     SeverityPolicyConfig
+		wantErr string
+	}{
+		{name: "Valid", cfg: SeverityPolicyConfig{Levels: map[string]SeverityPolicy{"info": {Action: SeverityActionSample, SampleRate: 1}}}},
+		{name: "UnknownLevel", cfg: SeverityPolicyConfig{Levels: map[string]SeverityPolicy{"notice": {Action: SeverityActionDrop}}}, wantErr: `unknown severity level "notice"`},
+		{name: "MissingAction", cfg: SeverityPolicyConfig{Levels: map[string]SeverityPolicy{"info": {}}}, wantErr: `requires an "action"`},
+		{name: "InvalidAction", cfg: SeverityPolicyConfig{Levels: map[string]SeverityPolicy{"info": {Action: "keepall"}}}, wantErr: `invalid action "keepall"`},
+		{name: "RateWithoutSample", cfg: SeverityPolicyConfig{Levels: map[string]SeverityPolicy{"info": {Action: SeverityActionDrop, SampleRate: 0.5}}}, wantErr: `"sample_rate" requires action == "sample"`},
+		{name: "RateOutOfRange", cfg: SeverityPolicyConfig{Levels: map[string]SeverityPolicy{"info": {Action: SeverityActionSample, SampleRate: 2}}}, wantErr: "invalid sample_rate 2"},
+		{name: "ConsistentWithoutLevels", cfg: SeverityPolicyConfig{ConsistentByTraceID: true}, wantErr: `"consistent_by_trace_id" requires "levels"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build cgo
+
+package azuregigwarmexporter
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	cgogeneva "github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter/internal/cgo"
+)
+
+// The soak test pushes logs and traces for SOAK_DURATION (a Go duration, e.g. "6h")
+// against a local Geneva stub that answers with a mix of successes and failures, and
+// periodically recreates the exporters, alternating between shutdown and dropping them
+// for the finalizers. It samples RSS, the Go heap and the live handle counts of the Rust
+// bridge, and fails if any of them grows monotonically. Like BenchmarkUploadBatch, it needs
+// the Rust bridge built with the mock_auth feature.
+//
+//	SOAK_DURATION=1h go test -run TestSoak -timeout 2h -v .
+
+const (
+	// soakWindows is the number of windows the samples after warm-up are split into.
+	// A series grows monotonically if the minimum of every window is above the
+	// minimum of the window before it.
+	soakWindows = 5
+	// soakWarmUp is the fraction of samples ignored while caches and pools fill up.
+	soakWarmUp = 0.2
+	// soakMemoryTolerance is the growth of RSS and Go heap, relative to the first
+	// window, that is accepted even when it is monotonic.
+	soakMemoryTolerance = 0.1
+	// soakGenerations is the number of times the exporters are recreated.
+	soakGenerations = 20
+)
+
+var soakShapes = []benchShape{
+	{name: "Small", records: 500, bodySize: 64, attrs: 3},
+	{name: "Huge", records: 5, bodySize: 64 << 10, attrs: 3},
+	{name: "AttributeHeavy", records: 100, bodySize: 64, attrs: 100},
+}
+
+// soakSample is one measurement of the process.
+type soakSample struct {
+	rss         uint64
+	heap        uint64
+	liveClients int64
+	liveBatches int64
+	batchBytes  int64
+}
+
+func TestSoak(t *testing.T) {
+	duration, err := time.ParseDuration(os.Getenv("SOAK_DURATION"))
+	if err != nil || duration <= 0 {
+		t.Skip("Set SOAK_DURATION to run the soak test")
+	}
+	sampleEvery := max(duration/60, time.Second)
+	recreateEvery := max(duration/soakGenerations, time.Second)
+
+	stub := newFlakyGenevaStub(t)
+	cfg := newBenchConfig(stub.server.URL)
+	cfg.DryRun = false
+	cfg.BatchRetryConfig = BatchRetryConfig{
+		Enabled:         true,
+		MaxRetries:      2,
+		InitialInterval: "10ms",
+		MaxInterval:     "100ms",
+		Multiplier:      2,
+	}
+
+	var (
+		logs       *logsExporter
+		traces     *tracesExporter
+		generation int
+	)
+	recreate := func() {
+		// Every other generation is dropped without shutdown, leaving its clients to the
+		// finalizers.
+		if logs != nil && generation%2 == 0 {
+			require.NoError(t, logs.shutdown(context.Background()))
+			require.NoError(t, traces.shutdown(context.Background()))
+		}
+		var err error
+		logs, err = newLogsExporter(context.Background(), newBenchSettings(), cfg)
+		require.NoError(t, err)
+		traces, err = newTracesExporter(context.Background(), newBenchSettings(), cfg)
+		require.NoError(t, err)
+		generation++
+	}
+	recreate()
+	defer func() {
+		_ = logs.shutdown(context.Background())
+		_ = traces.shutdown(context.Background())
+	}()
+
+	var (
+		samples    []soakSample
+		pushes     int
+		failures   int
+		start      = time.Now()
+		nextSample = start
+		nextCreate = start.Add(recreateEvery)
+	)
+	for now := start; now.Sub(start) < duration; now = time.Now() {
+		if now.After(nextCreate) {
+			recreate()
+			nextCreate = now.Add(recreateEvery)
+		}
+		if now.After(nextSample) {
+			samples = append(samples, takeSoakSample())
+			nextSample = now.Add(sampleEvery)
+		}
+
+		shape := soakShapes[pushes%len(soakShapes)]
+		if err := logs.pushLogs(context.Background(), benchLogs(shape)); err != nil {
+			failures++
+		}
+		if err := traces.pushTraces(context.Background(), benchTraces(shape)); err != nil {
+			failures++
+		}
+		pushes += 2
+	}
+	samples = append(samples, takeSoakSample())
+
+	t.Logf("pushes=%d failed=%d generations=%d responses=%v", pushes, failures, generation, stub.counts())
+	first, last := samples[0], samples[len(samples)-1]
+	t.Logf("rss=%d->%d heap=%d->%d live_clients=%d->%d live_batches=%d->%d",
+		first.rss, last.rss, first.heap, last.heap,
+		first.liveClients, last.liveClients, first.liveBatches, last.liveBatches)
+
+	if first.rss > 0 {
+		assertNoMonotonicGrowth(t, "RSS", samples, func(s soakSample) float64 { return float64(s.rss) }, soakMemoryTolerance)
+	}
+	assertNoMonotonicGrowth(t, "Go heap", samples, func(s soakSample) float64 { return float64(s.heap) }, soakMemoryTolerance)
+	assertNoMonotonicGrowth(t, "live client handles", samples, func(s soakSample) float64 { return float64(s.liveClients) }, 0)
+	assertNoMonotonicGrowth(t, "live batches handles", samples, func(s soakSample) float64 { return float64(s.liveBatches) }, 0)
+	assertNoMonotonicGrowth(t, "encoded batch bytes", samples, func(s soakSample) float64 { return float64(s.batchBytes) }, 0)
+}
+
+// takeSoakSample measures the process after collecting garbage and running finalizers,
+// so that only live memory and handles are counted.
+func takeSoakSample() soakSample {
+	runtime.GC()
+	runtime.GC()
+	// Finalizers run on their own goroutine after the collection that found them.
+	time.Sleep(10 * time.Millisecond)
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	stats := cgogeneva.BridgeStats()
+	return soakSample{
+		rss:         readRSS(),
+		heap:        ms.HeapAlloc,
+		liveClients: stats.LiveClients,
+		liveBatches: stats.LiveBatches,
+		batchBytes:  stats.BatchBytes,
+	}
+}
+
+// readRSS returns the resident set size of the process, or 0 where /proc is not available.
+func readRSS() uint64 {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * uint64(os.Getpagesize())
+}
+
+// assertNoMonotonicGrowth fails if the window minima of the series after warm-up grow
+// monotonically by more than tolerance, relative to the first window.
+func assertNoMonotonicGrowth(t *testing.T, name string, samples []soakSample, value func(soakSample) float64, tolerance float64) {
+	t.Helper()
+	samples = samples[int(float64(len(samples))*soakWarmUp):]
+	if len(samples) < 2*soakWindows {
+		t.Logf("%s: too few samples (%d) to check for growth", name, len(samples))
+		return
+	}
+	size := len(samples) / soakWindows
+	minima := make([]float64, soakWindows)
+	for w := range minima {
+		minima[w] = value(samples[w*size])
+		for _, s := range samples[w*size : (w+1)*size] {
+			minima[w] = min(minima[w], value(s))
+		}
+	}
+	for w := 1; w < soakWindows; w++ {
+		if minima[w] <= minima[w-1] {
+			return
+		}
+	}
+	growth := minima[soakWindows-1] - minima[0]
+	if growth > tolerance*minima[0] {
+		t.Errorf("%s grows monotonically: window minima %v", name, minima)
+	}
+}
+
+// flakyGenevaStub is a Geneva config service and ingestion gateway that fails a share of
+// the requests with server errors, throttling, auth failures and rejected payloads.
+type flakyGenevaStub struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	rng       *rand.Rand
+	responses map[int]int
+}
+
+func newFlakyGenevaStub(t *testing.T) *flakyGenevaStub {
+	s := &flakyGenevaStub{
+		rng:       rand.New(rand.NewPCG(1, 2)),
+		responses: map[int]int{},
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *flakyGenevaStub) handle(w http.ResponseWriter, req *http.Request) {
+	ingest := req.URL.Path == stubIngestPath
+	status := http.StatusOK
+	if ingest {
+		status = http.StatusAccepted
+	}
+	s.mu.Lock()
+	switch p := s.rng.Float64(); {
+	case p < 0.1:
+		status = http.StatusInternalServerError
+	case p < 0.15:
+		status = http.StatusTooManyRequests
+	case p < 0.2 && ingest:
+		status = http.StatusUnauthorized
+	case p < 0.25 && ingest:
+		status = http.StatusBadRequest
+	}
+	s.responses[status]++
+	s.mu.Unlock()
+
+	switch {
+	case status >= http.StatusBadRequest:
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		http.Error(w, http.StatusText(status), status)
+	case ingest:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"ticket":"soak"}`))
+	default:
+		writeStubConfig(w, s.server.URL)
+	}
+}
+
+// counts returns the number of responses sent per status code.
+func (s *flakyGenevaStub) counts() map[int]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make(map[int]int, len(s.responses))
+	for status, n := range s.responses {
+		counts[status] = n
+	}
+	return counts
+}
@@ -0,0 +1,286 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// SpanEventsModeKeep leaves span events on the span.
+	SpanEventsModeKeep = "keep"
+	// SpanEventsModeRows exports every span event as a separate row of a dedicated Geneva
+	// event and removes it from the span.
+	SpanEventsModeRows = "rows"
+	// SpanEventsModeDrop removes span events.
+	SpanEventsModeDrop = "drop"
+
+	// SpanLinksModeKeep leaves span links on the span.
+	SpanLinksModeKeep = "keep"
+	// SpanLinksModeJSON serializes span links into a single JSON string column.
+	SpanLinksModeJSON = "json"
+	// SpanLinksModeDrop removes span links.
+	SpanLinksModeDrop = "drop"
+
+	defaultSpanEventName   = "SpanEvent"
+	defaultSpanLinksColumn = "Links"
+
+	exceptionEventName       = "exception"
+	exceptionAttributePrefix = "exception."
+)
+
+// SpanEventsConfig configures how span events are exported.
+type SpanEventsConfig struct {
+	// Mode is "keep", "rows" or "drop" (default: "keep").
+	Mode string `mapstructure:"mode"`
+	// EventName is the Geneva event that receives span event rows in "rows" mode
+	// (default: "SpanEvent").
+	EventName string `mapstructure:"event_name"`
+	// PromoteExceptions copies the exception.* attributes of the last exception event of
+	// a span to the span's own attributes (columns).
+	PromoteExceptions bool `mapstructure:"promote_exceptions"`
+}
+
+// Validate checks if the span events configuration is valid.
+func (c *SpanEventsConfig) Validate() error {
+	switch c.Mode {
+	case "", SpanEventsModeKeep, SpanEventsModeRows, SpanEventsModeDrop:
+	default:
+		return fmt.Errorf(`invalid mode %q (must be %q, %q or %q)`, c.Mode, SpanEventsModeKeep, SpanEventsModeRows, SpanEventsModeDrop)
+	}
+	if c.EventName != "" {
+		if c.Mode != SpanEventsModeRows {
+			return errors.New(`"event_name" requires mode == "rows"`)
+		}
+		if !isValidGenevaName(c.EventName) {
+			return fmt.Errorf(`invalid event_name %q`, c.EventName)
+		}
+	}
+	return nil
+}
+
+// SpanLinksConfig configures how span links are exported.
+type SpanLinksConfig struct {
+	// Mode is "keep", "json" or "drop" (default: "keep").
+	Mode string `mapstructure:"mode"`
+	// Column receives the JSON array of links in "json" mode (default: "Links").
+	Column string `mapstructure:"column"`
+}
+
+// Validate checks if the span links configuration is valid.
+func (c *SpanLinksConfig) Validate() error {
+	switch c.Mode {
+	case "", SpanLinksModeKeep, SpanLinksModeJSON, SpanLinksModeDrop:
+	default:
+		return fmt.Errorf(`invalid mode %q (must be %q, %q or %q)`, c.Mode, SpanLinksModeKeep, SpanLinksModeJSON, SpanLinksModeDrop)
+	}
+	if c.Column != "" {
+		if c.Mode != SpanLinksModeJSON {
+			return errors.New(`"column" requires mode == "json"`)
+		}
+		if !isValidGenevaName(c.Column) {
+			return fmt.Errorf(`invalid column %q`, c.Column)
+		}
+	}
+	return nil
+}
+
+// spanTransformer applies the span events and links modes to traces.
+type spanTransformer struct {
+	eventsMode        string
+	eventName         string
+	promoteExceptions bool
+	linksMode         string
+	linksColumn       string
+}
+
+// newSpanTransformer creates a transformer, or returns nil if events and links are kept
+// unchanged and no exceptions are promoted.
+func newSpanTransformer(events SpanEventsConfig, links SpanLinksConfig) *spanTransformer {
+	t := &spanTransformer{
+		eventsMode:        events.Mode,
+		eventName:         events.EventName,
+		promoteExceptions: events.PromoteExceptions,
+		linksMode:         links.Mode,
+		linksColumn:       links.Column,
+	}
+	if t.eventsMode == "" {
+		t.eventsMode = SpanEventsModeKeep
+	}
+	if t.eventName == "" {
+		t.eventName = defaultSpanEventName
+	}
+	if t.linksMode == "" {
+		t.linksMode = SpanLinksModeKeep
+	}
+	if t.linksColumn == "" {
+		t.linksColumn = defaultSpanLinksColumn
+	}
+	if t.eventsMode == SpanEventsModeKeep && t.linksMode == SpanLinksModeKeep && !t.promoteExceptions {
+		return nil
+	}
+	return t
+}
+
+// eventRows returns the span events of td as log records of the configured Geneva event,
+// or an empty plog.Logs if events are not exported as rows. Each row carries the trace
+// and span ID of its span, the event timestamp, the event name as body and the event
+// attributes. td is not modified.
+func (t *spanTransformer) eventRows(td ptrace.Traces) plog.Logs {
+	ld := plog.NewLogs()
+	if t.eventsMode != SpanEventsModeRows {
+		return ld
+	}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		var rl plog.ResourceLogs
+		hasRL := false
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			var sl plog.ScopeLogs
+			hasSL := false
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				for e := 0; e < span.Events().Len(); e++ {
+					event := span.Events().At(e)
+					if !hasRL {
+						rl = ld.ResourceLogs().AppendEmpty()
+						rs.Resource().CopyTo(rl.Resource())
+						rl.SetSchemaUrl(rs.SchemaUrl())
+						hasRL = true
+					}
+					if !hasSL {
+						sl = rl.ScopeLogs().AppendEmpty()
+						ss.Scope().CopyTo(sl.Scope())
+						sl.SetSchemaUrl(ss.SchemaUrl())
+						hasSL = true
+					}
+					lr := sl.LogRecords().AppendEmpty()
+					lr.SetEventName(t.eventName)
+					lr.SetTimestamp(event.Timestamp())
+					lr.SetObservedTimestamp(event.Timestamp())
+					lr.SetTraceID(span.TraceID())
+					lr.SetSpanID(span.SpanID())
+					lr.SetFlags(plog.LogRecordFlags(span.Flags() & 0xff))
+					lr.Body().SetStr(event.Name())
+					event.Attributes().CopyTo(lr.Attributes())
+					lr.Attributes().PutStr("span.name", span.Name())
+					lr.SetDroppedAttributesCount(event.DroppedAttributesCount())
+				}
+			}
+		}
+	}
+	return ld
+}
+
+// transformTraces applies the events and links modes to every span in td. In "rows" mode,
+// eventRows must be called before transformTraces because the events are removed here.
+// Transforming already transformed spans leaves them unchanged.
+func (t *spanTransformer) transformTraces(td ptrace.Traces) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				t.transformSpan(spans.At(k))
+			}
+		}
+	}
+}
+
+// exportChunks is exportTracesChunks for a transformer. The events of each chunk of src are
+// exported as rows with exportRows right before the chunk's spans, so that they go out
+// together. A chunk whose rows went out but whose spans failed is handed back for retry
+// transformed, so that the retry does not export its events a second time.
+func (t *spanTransformer) exportChunks(src, prepared ptrace.Traces, plan chunkPlan, exportRows func(plog.Logs) error, export func(int, ptrace.Traces) error) (int, error) {
+	rowsSent := make(map[int]bool)
+	return exportTracesChunks(src, prepared, plan, func(i int, chunk ptrace.Traces) error {
+		if rows := t.eventRows(tracesChunk(src, plan, i)); rows.LogRecordCount() > 0 {
+			if err := exportRows(rows); err != nil {
+				return err
+			}
+			rowsSent[i] = true
+		}
+		return export(i, chunk)
+	}, func(i int, retry ptrace.Traces) {
+		if rowsSent[i] {
+			t.transformTraces(retry)
+		}
+	})
+}
+
+// transformSpan applies the events and links modes to a single span.
+func (t *spanTransformer) transformSpan(span ptrace.Span) {
+	if t.promoteExceptions {
+		promoteExceptionAttributes(span)
+	}
+	if t.eventsMode != SpanEventsModeKeep {
+		span.Events().RemoveIf(func(ptrace.SpanEvent) bool { return true })
+	}
+
+	switch t.linksMode {
+	case SpanLinksModeJSON:
+		if span.Links().Len() > 0 {
+			if b, err := json.Marshal(spanLinksToRaw(span.Links())); err == nil {
+				span.Attributes().PutStr(t.linksColumn, string(b))
+			}
+		}
+		span.Links().RemoveIf(func(ptrace.SpanLink) bool { return true })
+	case SpanLinksModeDrop:
+		span.Links().RemoveIf(func(ptrace.SpanLink) bool { return true })
+	}
+}
+
+// promoteExceptionAttributes copies the exception.* attributes of the span's exception
+// events to the span attributes. The last exception event wins; attributes the span
+// already sets keep their value.
+func promoteExceptionAttributes(span ptrace.Span) {
+	attrs := span.Attributes()
+	// Walk backwards so that the last exception event sets the value
+	for e := span.Events().Len() - 1; e >= 0; e-- {
+		event := span.Events().At(e)
+		if event.Name() != exceptionEventName {
+			continue
+		}
+		event.Attributes().Range(func(k string, v pcommon.Value) bool {
+			if !strings.HasPrefix(k, exceptionAttributePrefix) {
+				return true
+			}
+			if _, ok := attrs.Get(k); !ok {
+				v.CopyTo(attrs.PutEmpty(k))
+			}
+			return true
+		})
+	}
+}
+
+// spanLinksToRaw converts span links to a JSON-friendly representation.
+func spanLinksToRaw(links ptrace.SpanLinkSlice) []map[string]any {
+	out := make([]map[string]any, 0, links.Len())
+	for i := 0; i < links.Len(); i++ {
+		link := links.At(i)
+		traceID := link.TraceID()
+		spanID := link.SpanID()
+		raw := map[string]any{
+			"traceId": hex.EncodeToString(traceID[:]),
+			"spanId":  hex.EncodeToString(spanID[:]),
+		}
+		if ts := link.TraceState().AsRaw(); ts != "" {
+			raw["traceState"] = ts
+		}
+		if link.Attributes().Len() > 0 {
+			raw["attributes"] = link.Attributes().AsRaw()
+		}
+		out = append(out, raw)
+	}
+	return out
+}
@@ -0,0 +1,228 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// newSpanEventsTestTraces returns one span with an exception event, a second event and
+// two links.
+func newSpanEventsTestTraces() (ptrace.Traces, ptrace.Span) {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("http")
+	span := ss.Spans().AppendEmpty()
+	span.SetName("GET /cart")
+	span.SetTraceID(testTraceID)
+	span.SetSpanID(testSpanID)
+	span.SetFlags(1)
+	span.Attributes().PutStr("exception.type", "SpanOwnType")
+
+	ev := span.Events().AppendEmpty()
+	ev.SetName("exception")
+	ev.SetTimestamp(pcommon.Timestamp(1000))
+	ev.Attributes().PutStr("exception.type", "IOError")
+	ev.Attributes().PutStr("exception.message", "disk full")
+	ev.Attributes().PutStr("other", "x")
+	ev.SetDroppedAttributesCount(2)
+
+	ev = span.Events().AppendEmpty()
+	ev.SetName("cache.miss")
+	ev.SetTimestamp(pcommon.Timestamp(2000))
+
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(testTraceID)
+	link.SetSpanID(testSpanID)
+	link.TraceState().FromRaw("vendor=1")
+	link.Attributes().PutStr("kind", "follows")
+	link = span.Links().AppendEmpty()
+	link.SetTraceID(pcommon.TraceID([16]byte{1}))
+	link.SetSpanID(pcommon.SpanID([8]byte{2}))
+	return td, span
+}
+
+func TestSpanEventRows(t *testing.T) {
+	tr := newSpanTransformer(SpanEventsConfig{Mode: SpanEventsModeRows, EventName: "Events"}, SpanLinksConfig{})
+	require.NotNil(t, tr)
+	td, span := newSpanEventsTestTraces()
+
+	rows := tr.eventRows(td)
+	require.Equal(t, 2, rows.LogRecordCount())
+	assert.Equal(t, 2, span.Events().Len(), "eventRows does not modify the spans")
+
+	rl := rows.ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{"service.name": "checkout"}, rl.Resource().Attributes().AsRaw())
+	assert.Equal(t, "http", rl.ScopeLogs().At(0).Scope().Name())
+
+	first := rl.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "Events", first.EventName())
+	assert.Equal(t, "exception", first.Body().Str())
+	assert.Equal(t, pcommon.Timestamp(1000), first.Timestamp())
+	assert.Equal(t, pcommon.Timestamp(1000), first.ObservedTimestamp())
+	assert.Equal(t, testTraceID, first.TraceID())
+	assert.Equal(t, testSpanID, first.SpanID())
+	assert.Equal(t, plog.LogRecordFlags(1), first.Flags())
+	assert.Equal(t, uint32(2), first.DroppedAttributesCount())
+	assert.Equal(t, map[string]any{
+		"exception.type":    "IOError",
+		"exception.message": "disk full",
+		"other":             "x",
+		"span.name":         "GET /cart",
+	}, first.Attributes().AsRaw())
+
+	second := rl.ScopeLogs().At(0).LogRecords().At(1)
+	assert.Equal(t, "cache.miss", second.Body().Str())
+	assert.Equal(t, map[string]any{"span.name": "GET /cart"}, second.Attributes().AsRaw())
+
+	tr.transformTraces(td)
+	assert.Zero(t, span.Events().Len(), "rows are removed from the span")
+	assert.Equal(t, 2, span.Links().Len())
+}
+
+func TestSpanEventRowsOtherModes(t *testing.T) {
+	tr := newSpanTransformer(SpanEventsConfig{Mode: SpanEventsModeDrop}, SpanLinksConfig{})
+	td, span := newSpanEventsTestTraces()
+	assert.Zero(t, tr.eventRows(td).LogRecordCount())
+	tr.transformTraces(td)
+	assert.Zero(t, span.Events().Len())
+
+	assert.Nil(t, newSpanTransformer(SpanEventsConfig{}, SpanLinksConfig{}))
+}
+
+func TestSpanEventRowsExportChunks(t *testing.T) {
+	tr := newSpanTransformer(SpanEventsConfig{Mode: SpanEventsModeRows, PromoteExceptions: true}, SpanLinksConfig{})
+	newTraces := func(spans int) ptrace.Traces {
+		td, span := newSpanEventsTestTraces()
+		for k := 1; k < spans; k++ {
+			span.CopyTo(td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().AppendEmpty())
+		}
+		return td
+	}
+	// export runs one push of td in chunks of one span. The rows of chunk failRows and the
+	// spans of chunk failSpans fail; it returns the failed chunks, the row chunks exported
+	// and the error.
+	export := func(td ptrace.Traces, failRows, failSpans int) (int, int, error) {
+		prepared := ptrace.NewTraces()
+		td.CopyTo(prepared)
+		tr.transformTraces(prepared)
+		plan, _ := planTracesByEvent(prepared, 1, 0, nil)
+		rowChunks := 0
+		failed, err := tr.exportChunks(td, prepared, plan, func(rows plog.Logs) error {
+			defer func() { rowChunks++ }()
+			assert.Equal(t, 2, rows.LogRecordCount(), "each chunk exports the rows of its own span")
+			if rowChunks == failRows {
+				return errors.New("upload failed")
+			}
+			return nil
+		}, func(i int, chunk ptrace.Traces) error {
+			assert.Zero(t, chunk.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().Len())
+			if i == failSpans {
+				return errors.New("upload failed")
+			}
+			return nil
+		})
+		return failed, rowChunks, err
+	}
+	retried := func(t *testing.T, err error) ptrace.Traces {
+		var partial consumererror.Traces
+		require.True(t, errors.As(err, &partial))
+		require.Equal(t, 1, partial.Data().SpanCount())
+		return partial.Data()
+	}
+	firstSpan := func(td ptrace.Traces) ptrace.Span {
+		return td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	}
+
+	t.Run("spans fail after rows", func(t *testing.T) {
+		td := newTraces(2)
+		failed, rowChunks, err := export(td, -1, 1)
+		assert.Equal(t, 1, failed)
+		assert.Equal(t, 2, rowChunks)
+		retry := retried(t, err)
+		span := firstSpan(retry)
+		assert.Zero(t, span.Events().Len(), "events whose rows went out are not handed back")
+		v, _ := span.Attributes().Get("exception.message")
+		assert.Equal(t, "disk full", v.Str(), "promoted exceptions are kept")
+		assert.Equal(t, 2, td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1).Events().Len(), "the request is not modified")
+
+		failed, rowChunks, err = export(retry, -1, -1)
+		require.NoError(t, err)
+		assert.Zero(t, failed)
+		assert.Zero(t, rowChunks, "the retry does not export the rows again")
+	})
+
+	t.Run("rows fail", func(t *testing.T) {
+		failed, rowChunks, err := export(newTraces(2), 1, -1)
+		assert.Equal(t, 1, failed)
+		assert.Equal(t, 2, rowChunks)
+		assert.Equal(t, 2, firstSpan(retried(t, err)).Events().Len(), "events are retried if their rows failed")
+	})
+
+	t.Run("only chunk fails after rows", func(t *testing.T) {
+		td := newTraces(1)
+		failed, _, err := export(td, -1, 0)
+		assert.Equal(t, 1, failed)
+		assert.Zero(t, firstSpan(retried(t, err)).Events().Len())
+		assert.Equal(t, 2, firstSpan(td).Events().Len(), "the request is not modified")
+	})
+}
+
+func TestSpanLinks(t *testing.T) {
+	tr := newSpanTransformer(SpanEventsConfig{}, SpanLinksConfig{Mode: SpanLinksModeJSON, Column: "SpanLinks"})
+	require.NotNil(t, tr)
+	td, span := newSpanEventsTestTraces()
+	tr.transformTraces(td)
+
+	assert.Zero(t, span.Links().Len())
+	assert.Equal(t, 2, span.Events().Len(), "events are kept")
+	links, ok := span.Attributes().Get("SpanLinks")
+	require.True(t, ok)
+	assert.JSONEq(t, `[
+		{"traceId":"4bf92f3577b34da6a3ce929d0e0e4736","spanId":"00f067aa0ba902b7","traceState":"vendor=1","attributes":{"kind":"follows"}},
+		{"traceId":"01000000000000000000000000000000","spanId":"0200000000000000"}
+	]`, links.Str())
+
+	tr = newSpanTransformer(SpanEventsConfig{}, SpanLinksConfig{Mode: SpanLinksModeDrop})
+	td, span = newSpanEventsTestTraces()
+	tr.transformTraces(td)
+	assert.Zero(t, span.Links().Len())
+	_, ok = span.Attributes().Get(defaultSpanLinksColumn)
+	assert.False(t, ok)
+}
+
+func TestPromoteExceptions(t *testing.T) {
+	tr := newSpanTransformer(SpanEventsConfig{PromoteExceptions: true}, SpanLinksConfig{})
+	require.NotNil(t, tr)
+	td, span := newSpanEventsTestTraces()
+	later := span.Events().AppendEmpty()
+	later.SetName("exception")
+	later.Attributes().PutStr("exception.message", "disk still full")
+
+	tr.transformTraces(td)
+	assert.Equal(t, map[string]any{
+		"exception.type":    "SpanOwnType",
+		"exception.message": "disk still full",
+	}, span.Attributes().AsRaw(), "the last exception event wins and span attributes are kept")
+	assert.Equal(t, 3, span.Events().Len())
+}
+
+func TestSpanEventsConfigValidate(t *testing.T) {
+	assert.NoError(t, (&SpanEventsConfig{Mode: SpanEventsModeRows, EventName: "Events"}).Validate())
+	assert.ErrorContains(t, (&SpanEventsConfig{Mode: "split"}).Validate(), `invalid mode "split"`)
+	assert.ErrorContains(t, (&SpanEventsConfig{EventName: "Events"}).Validate(), `requires mode == "rows"`)
+	assert.ErrorContains(t, (&SpanEventsConfig{Mode: SpanEventsModeRows, EventName: "span-events"}).Validate(), `invalid event_name`)
+	assert.NoError(t, (&SpanLinksConfig{Mode: SpanLinksModeJSON, Column: "Links"}).Validate())
+	assert.ErrorContains(t, (&SpanLinksConfig{Column: "Links"}).Validate(), `requires mode == "json"`)
+}
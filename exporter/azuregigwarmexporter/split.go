@@ -0,0 +1,349 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// protoFieldOverhead returns the number of bytes a length-delimited protobuf field
+// of the given size adds to its parent message (1 byte tag + varint length).
+func protoFieldOverhead(size int) int {
+	n := 1
+	for v := uint64(size); v >= 0x80; v >>= 7 {
+		n++
+	}
+	return n + 1
+}
+
+// chunkLimits holds the per-chunk limits used when splitting a request.
+// A zero limit disables the corresponding check.
+type chunkLimits struct {
+	maxItems int
+	maxBytes int
+}
+
+// fits reports whether a request with the given item count and encoded size needs no splitting.
+func (l chunkLimits) fits(items, size int) bool {
+	return (l.maxItems <= 0 || items <= l.maxItems) && (l.maxBytes <= 0 || size <= l.maxBytes)
+}
+
+// full reports whether adding an item of itemSize bytes to a chunk currently holding
+// items items and size bytes would exceed the limits. An empty chunk is never full,
+// so a single oversized item still gets a chunk of its own.
+func (l chunkLimits) full(items, size, itemSize int) bool {
+	if items == 0 {
+		return false
+	}
+	return !l.fits(items+1, size+itemSize)
+}
+
+// recordRange is a run of consecutive records (log records or spans) of one scope of a request.
+type recordRange struct {
+	resource, scope int
+	start, end      int
+}
+
+// chunkPlan lists the record ranges of every chunk of a split request. A nil plan leaves
+// the request in one piece.
+type chunkPlan [][]recordRange
+
+// chunks returns the number of chunks of the plan.
+func (p chunkPlan) chunks() int {
+	if p == nil {
+		return 1
+	}
+	return len(p)
+}
+
+// planner builds a chunkPlan record by record.
+type planner struct {
+	limits       chunkLimits
+	plan         chunkPlan
+	cur          []recordRange
+	items, bytes int
+}
+
+// hasResource reports whether the current chunk already holds a record of resource i.
+func (p *planner) hasResource(i int) bool {
+	return len(p.cur) > 0 && p.cur[len(p.cur)-1].resource == i
+}
+
+// hasScope reports whether the current chunk already holds a record of scope j of resource i.
+func (p *planner) hasScope(i, j int) bool {
+	return p.hasResource(i) && p.cur[len(p.cur)-1].scope == j
+}
+
+// add appends record k of scope j of resource i. recordSize is the encoded size of the
+// record and resourceOverhead and scopeOverhead are what its resource and scope add to a
+// chunk that does not hold them yet.
+func (p *planner) add(i, j, k, recordSize, resourceOverhead, scopeOverhead int) {
+	itemSize := recordSize
+	if !p.hasResource(i) {
+		itemSize += resourceOverhead
+	}
+	if !p.hasScope(i, j) {
+		itemSize += scopeOverhead
+	}
+	if p.limits.full(p.items, p.bytes, itemSize) {
+		p.flush()
+		itemSize = recordSize + resourceOverhead + scopeOverhead
+	}
+	if p.hasScope(i, j) && p.cur[len(p.cur)-1].end == k {
+		p.cur[len(p.cur)-1].end++
+	} else {
+		p.cur = append(p.cur, recordRange{resource: i, scope: j, start: k, end: k + 1})
+	}
+	p.items++
+	p.bytes += itemSize
+}
+
+// flush closes the current chunk.
+func (p *planner) flush() {
+	if p.items > 0 {
+		p.plan = append(p.plan, p.cur)
+	}
+	p.cur = nil
+	p.items, p.bytes = 0, 0
+}
+
+// planLogs splits ld along resource and scope boundaries into chunks that hold at most
+// maxRecords log records and approximately at most maxBytes of protobuf-encoded data.
+// It returns nil if no splitting is needed.
+func planLogs(ld plog.Logs, maxRecords, maxBytes int) chunkPlan {
+	limits := chunkLimits{maxItems: maxRecords, maxBytes: maxBytes}
+	sizer := &plog.ProtoMarshaler{}
+	if limits.fits(ld.LogRecordCount(), sizer.LogsSize(ld)) {
+		return nil
+	}
+
+	p := &planner{limits: limits}
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		rlOverhead := sizer.ResourceLogsSize(rl)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			slSize := sizer.ScopeLogsSize(rl.ScopeLogs().At(j))
+			rlOverhead -= slSize + protoFieldOverhead(slSize)
+		}
+
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			slOverhead := sizer.ScopeLogsSize(sl)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lrSize := sizer.LogRecordSize(sl.LogRecords().At(k))
+				slOverhead -= lrSize + protoFieldOverhead(lrSize)
+			}
+
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lrSize := sizer.LogRecordSize(sl.LogRecords().At(k))
+				p.add(i, j, k, lrSize+protoFieldOverhead(lrSize),
+					rlOverhead+protoFieldOverhead(rlOverhead), slOverhead+protoFieldOverhead(slOverhead))
+			}
+		}
+	}
+	p.flush()
+	return p.plan
+}
+
+// logsChunk returns chunk i of plan cut out of ld. Each chunk carries a copy of the
+// resource and scope of the records it contains. With a nil plan, ld itself is returned.
+func logsChunk(ld plog.Logs, plan chunkPlan, i int) plog.Logs {
+	if plan == nil {
+		return ld
+	}
+	chunk := plog.NewLogs()
+	var curRL plog.ResourceLogs
+	var curSL plog.ScopeLogs
+	lastResource, lastScope := -1, -1
+	for _, r := range plan[i] {
+		rl := ld.ResourceLogs().At(r.resource)
+		if r.resource != lastResource {
+			curRL = chunk.ResourceLogs().AppendEmpty()
+			rl.Resource().CopyTo(curRL.Resource())
+			curRL.SetSchemaUrl(rl.SchemaUrl())
+			lastResource, lastScope = r.resource, -1
+		}
+		sl := rl.ScopeLogs().At(r.scope)
+		if r.scope != lastScope {
+			curSL = curRL.ScopeLogs().AppendEmpty()
+			sl.Scope().CopyTo(curSL.Scope())
+			curSL.SetSchemaUrl(sl.SchemaUrl())
+			lastScope = r.scope
+		}
+		for k := r.start; k < r.end; k++ {
+			sl.LogRecords().At(k).CopyTo(curSL.LogRecords().AppendEmpty())
+		}
+	}
+	return chunk
+}
+
+// exportLogsChunks calls export for every chunk of plan cut out of prepared, the request
+// as handed to the encoder. When some but not all chunks fail, the records of the failed
+// chunks are cut out of src, the request as received, and returned in a
+// consumererror.Logs so that exporterhelper retries only those. src and prepared must
+// hold the same resources, scopes and records. It returns the number of failed chunks.
+func exportLogsChunks(src, prepared plog.Logs, plan chunkPlan, export func(plog.Logs) error) (int, error) {
+	var firstErr error
+	var failed []int
+	for i := 0; i < plan.chunks(); i++ {
+		if err := export(logsChunk(prepared, plan, i)); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed = append(failed, i)
+		}
+	}
+	if firstErr == nil || len(failed) == plan.chunks() {
+		return len(failed), firstErr
+	}
+	retry := plog.NewLogs()
+	for _, i := range failed {
+		logsChunk(src, plan, i).ResourceLogs().MoveAndAppendTo(retry.ResourceLogs())
+	}
+	return len(failed), consumererror.NewLogs(firstErr, retry)
+}
+
+// planTracesByEvent splits td along resource and scope boundaries into chunks that hold
+// at most maxSpans spans and approximately at most maxBytes of protobuf-encoded data. It
+// returns a nil plan if no splitting is needed. Chunks never mix Geneva events: the spans of each event are planned separately, in the order the events first
+// appear, and the event name of every chunk is returned with the plan. With nil names all
+// spans keep the encoder's default event "".
+func planTracesByEvent(td ptrace.Traces, maxSpans, maxBytes int, names spanEventNames) (chunkPlan, []string) {
+	limits := chunkLimits{maxItems: maxSpans, maxBytes: maxBytes}
+	sizer := &ptrace.ProtoMarshaler{}
+	events := []string{""}
+	if names != nil {
+		events = names.distinct()
+	}
+	if len(events) <= 1 && limits.fits(td.SpanCount(), sizer.TracesSize(td)) {
+		return nil, events
+	}
+
+	planners := make(map[string]*planner, len(events))
+	for _, event := range events {
+		planners[event] = &planner{limits: limits}
+	}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		rsOverhead := sizer.ResourceSpansSize(rs)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ssSize := sizer.ScopeSpansSize(rs.ScopeSpans().At(j))
+			rsOverhead -= ssSize + protoFieldOverhead(ssSize)
+		}
+
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			ssOverhead := sizer.ScopeSpansSize(ss)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				spanSize := sizer.SpanSize(ss.Spans().At(k))
+				ssOverhead -= spanSize + protoFieldOverhead(spanSize)
+			}
+
+			for k := 0; k < ss.Spans().Len(); k++ {
+				p := planners[""]
+				if names != nil {
+					p = planners[names[i][j][k]]
+				}
+				spanSize := sizer.SpanSize(ss.Spans().At(k))
+				p.add(i, j, k, spanSize+protoFieldOverhead(spanSize),
+					rsOverhead+protoFieldOverhead(rsOverhead), ssOverhead+protoFieldOverhead(ssOverhead))
+			}
+		}
+	}
+
+	var plan chunkPlan
+	var chunkEvents []string
+	for _, event := range events {
+		p := planners[event]
+		p.flush()
+		plan = append(plan, p.plan...)
+		for range p.plan {
+			chunkEvents = append(chunkEvents, event)
+		}
+	}
+	return plan, chunkEvents
+}
+
+// tracesChunk returns chunk i of plan cut out of td. Each chunk carries a copy of the
+// resource and scope of the spans it contains. With a nil plan, td itself is returned.
+func tracesChunk(td ptrace.Traces, plan chunkPlan, i int) ptrace.Traces {
+	if plan == nil {
+		return td
+	}
+	chunk := ptrace.NewTraces()
+	var curRS ptrace.ResourceSpans
+	var curSS ptrace.ScopeSpans
+	lastResource, lastScope := -1, -1
+	for _, r := range plan[i] {
+		rs := td.ResourceSpans().At(r.resource)
+		if r.resource != lastResource {
+			curRS = chunk.ResourceSpans().AppendEmpty()
+			rs.Resource().CopyTo(curRS.Resource())
+			curRS.SetSchemaUrl(rs.SchemaUrl())
+			lastResource, lastScope = r.resource, -1
+		}
+		ss := rs.ScopeSpans().At(r.scope)
+		if r.scope != lastScope {
+			curSS = curRS.ScopeSpans().AppendEmpty()
+			ss.Scope().CopyTo(curSS.Scope())
+			curSS.SetSchemaUrl(ss.SchemaUrl())
+			lastScope = r.scope
+		}
+		for k := r.start; k < r.end; k++ {
+			ss.Spans().At(k).CopyTo(curSS.Spans().AppendEmpty())
+		}
+	}
+	return chunk
+}
+
+// exportTracesChunks is exportLogsChunks for traces. export also receives the index of the
+// chunk in plan. If retryChunk is not nil, it is applied to the part of src handed back for
+// each failed chunk, and the adjusted data is returned for retry even if all chunks failed.
+func exportTracesChunks(src, prepared ptrace.Traces, plan chunkPlan, export func(int, ptrace.Traces) error, retryChunk func(int, ptrace.Traces)) (int, error) {
+	var firstErr error
+	var failed []int
+	for i := 0; i < plan.chunks(); i++ {
+		if err := export(i, tracesChunk(prepared, plan, i)); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed = append(failed, i)
+		}
+	}
+	if firstErr == nil || (len(failed) == plan.chunks() && retryChunk == nil) {
+		return len(failed), firstErr
+	}
+	retry := ptrace.NewTraces()
+	for _, i := range failed {
+		chunk := tracesChunk(src, plan, i)
+		if plan == nil {
+			// src itself is the only chunk; leave it unchanged
+			chunk = ptrace.NewTraces()
+			src.CopyTo(chunk)
+		}
+		if retryChunk != nil {
+			retryChunk(i, chunk)
+		}
+		chunk.ResourceSpans().MoveAndAppendTo(retry.ResourceSpans())
+	}
+	return len(failed), consumererror.NewTraces(firstErr, retry)
+}
+
+// exportStats describes what was encoded (and, outside dry-run mode, uploaded) for one or
+// more chunks of a request.
+type exportStats struct {
+	records int
+	batches int
+	// bytes is the size of the compressed batch payloads produced by the encoder
+	bytes int
+}
+
+// add accumulates other into s.
+func (s *exportStats) add(other exportStats) {
+	s.records += other.records
+	s.batches += other.batches
+	s.bytes += other.bytes
+}
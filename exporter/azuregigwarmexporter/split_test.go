@@ -0,0 +1,268 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// newSplitTestLogs returns logs with the given number of records per scope and scopes per
+// resource. Record bodies are "r<resource>-s<scope>-<record>" padded to bodySize.
+func newSplitTestLogs(resources, scopes, records, bodySize int) plog.Logs {
+	ld := plog.NewLogs()
+	for i := 0; i < resources; i++ {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", fmt.Sprintf("svc-%d", i))
+		rl.SetSchemaUrl("https://opentelemetry.io/schemas/1.26.0")
+		for j := 0; j < scopes; j++ {
+			sl := rl.ScopeLogs().AppendEmpty()
+			sl.Scope().SetName(fmt.Sprintf("scope-%d", j))
+			for k := 0; k < records; k++ {
+				body := fmt.Sprintf("r%d-s%d-%d", i, j, k)
+				sl.LogRecords().AppendEmpty().Body().SetStr(body + strings.Repeat(" ", max(bodySize-len(body), 0)))
+			}
+		}
+	}
+	return ld
+}
+
+// logBodies returns "<service.name>/<scope>/<body>" for every record of ld, in order.
+func logBodies(ld plog.Logs) []string {
+	var out []string
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		svc, _ := rl.Resource().Attributes().Get("service.name")
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				out = append(out, svc.Str()+"/"+sl.Scope().Name()+"/"+strings.TrimSpace(sl.LogRecords().At(k).Body().Str()))
+			}
+		}
+	}
+	return out
+}
+
+func TestSplitLogs(t *testing.T) {
+	tests := []struct {
+		name       string
+		ld         plog.Logs
+		maxRecords int
+		maxBytes   int
+		// wantCounts is the number of records per chunk
+		wantCounts []int
+	}{
+		{
+			name:       "NoLimits",
+			ld:         newSplitTestLogs(2, 2, 5, 10),
+			wantCounts: []int{20},
+		},
+		{
+			name:       "WithinLimits",
+			ld:         newSplitTestLogs(1, 1, 5, 10),
+			maxRecords: 5,
+			maxBytes:   1 << 20,
+			wantCounts: []int{5},
+		},
+		{
+			name:       "MaxRecords",
+			ld:         newSplitTestLogs(1, 1, 5, 10),
+			maxRecords: 2,
+			wantCounts: []int{2, 2, 1},
+		},
+		{
+			name:       "AcrossScopesAndResources",
+			ld:         newSplitTestLogs(2, 2, 2, 10),
+			maxRecords: 3,
+			wantCounts: []int{3, 3, 2},
+		},
+		{
+			name:       "MaxBytes",
+			ld:         newSplitTestLogs(1, 1, 6, 1000),
+			maxBytes:   2500,
+			wantCounts: []int{2, 2, 2},
+		},
+		{
+			name:       "OversizedRecord",
+			ld:         newSplitTestLogs(1, 1, 3, 5000),
+			maxBytes:   1000,
+			wantCounts: []int{1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planLogs(tt.ld, tt.maxRecords, tt.maxBytes)
+			chunks := make([]plog.Logs, plan.chunks())
+			var counts []int
+			var bodies []string
+			sizer := &plog.ProtoMarshaler{}
+			for i := range chunks {
+				chunk := logsChunk(tt.ld, plan, i)
+				chunks[i] = chunk
+				counts = append(counts, chunk.LogRecordCount())
+				bodies = append(bodies, logBodies(chunk)...)
+				if tt.maxBytes > 0 && chunk.LogRecordCount() > 1 {
+					assert.LessOrEqual(t, sizer.LogsSize(chunk), tt.maxBytes)
+				}
+			}
+			assert.Equal(t, tt.wantCounts, counts)
+			// Records keep their order, resource and scope
+			assert.Equal(t, logBodies(tt.ld), bodies)
+			if len(chunks) == 1 {
+				assert.Equal(t, tt.ld, chunks[0], "an unsplit request is passed through")
+			} else {
+				assert.Equal(t, "https://opentelemetry.io/schemas/1.26.0", chunks[1].ResourceLogs().At(0).SchemaUrl())
+			}
+		})
+	}
+}
+
+func TestSplitTraces(t *testing.T) {
+	td := ptrace.NewTraces()
+	for i := 0; i < 2; i++ {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", fmt.Sprintf("svc-%d", i))
+		for j := 0; j < 2; j++ {
+			ss := rs.ScopeSpans().AppendEmpty()
+			ss.Scope().SetName(fmt.Sprintf("scope-%d", j))
+			for k := 0; k < 3; k++ {
+				ss.Spans().AppendEmpty().SetName(fmt.Sprintf("r%d-s%d-%d", i, j, k))
+			}
+		}
+	}
+
+	plan, events := planTracesByEvent(td, 4, 0, nil)
+	require.Equal(t, 3, plan.chunks())
+	assert.Equal(t, []string{"", "", ""}, events)
+	var names []string
+	for c := 0; c < plan.chunks(); c++ {
+		chunk := tracesChunk(td, plan, c)
+		assert.LessOrEqual(t, chunk.SpanCount(), 4)
+		for i := 0; i < chunk.ResourceSpans().Len(); i++ {
+			rs := chunk.ResourceSpans().At(i)
+			svc, _ := rs.Resource().Attributes().Get("service.name")
+			for j := 0; j < rs.ScopeSpans().Len(); j++ {
+				ss := rs.ScopeSpans().At(j)
+				for k := 0; k < ss.Spans().Len(); k++ {
+					name := ss.Spans().At(k).Name()
+					assert.True(t, strings.HasPrefix(name, "r"+strings.TrimPrefix(svc.Str(), "svc-")+"-s"+strings.TrimPrefix(ss.Scope().Name(), "scope-")),
+						"span %s carries resource %s and scope %s", name, svc.Str(), ss.Scope().Name())
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	assert.Len(t, names, 12)
+	assert.Equal(t, "r0-s0-0", names[0])
+	assert.Equal(t, "r1-s1-2", names[11])
+}
+
+func TestExportLogsChunks(t *testing.T) {
+	errUpload := errors.New("upload failed")
+
+	tests := []struct {
+		name string
+		// fail selects the chunks whose export fails
+		fail        func(chunk int) bool
+		wantFailed  int
+		wantErr     bool
+		wantPartial []string
+	}{
+		{
+			name: "AllSucceed",
+			fail: func(int) bool { return false },
+		},
+		{
+			name:        "SomeFail",
+			fail:        func(chunk int) bool { return chunk == 1 },
+			wantFailed:  1,
+			wantErr:     true,
+			wantPartial: []string{"svc-0/scope-1/r0-s1-1", "svc-1/scope-0/r1-s0-0", "svc-1/scope-0/r1-s0-1"},
+		},
+		{
+			name:       "AllFail",
+			fail:       func(int) bool { return true },
+			wantFailed: 3,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newSplitTestLogs(2, 2, 2, 10)
+			// The prepared request is a rewritten copy; retries must use the records of src
+			prepared := plog.NewLogs()
+			src.CopyTo(prepared)
+			for i := 0; i < prepared.ResourceLogs().Len(); i++ {
+				prepared.ResourceLogs().At(i).Resource().Attributes().PutStr("prepared", "true")
+			}
+			plan := planLogs(prepared, 3, 0)
+			require.Equal(t, 3, plan.chunks())
+
+			chunk := 0
+			var exported int
+			failed, err := exportLogsChunks(src, prepared, plan, func(ld plog.Logs) error {
+				defer func() { chunk++ }()
+				_, ok := ld.ResourceLogs().At(0).Resource().Attributes().Get("prepared")
+				assert.True(t, ok, "chunks are cut out of the prepared request")
+				if tt.fail(chunk) {
+					return errUpload
+				}
+				exported += ld.LogRecordCount()
+				return nil
+			})
+			assert.Equal(t, tt.wantFailed, failed)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, 8, exported)
+				return
+			}
+			require.ErrorIs(t, err, errUpload)
+
+			var partial consumererror.Logs
+			if tt.wantPartial == nil {
+				assert.False(t, errors.As(err, &partial), "a request that failed as a whole is retried as is")
+				return
+			}
+			require.True(t, errors.As(err, &partial))
+			assert.Equal(t, tt.wantPartial, logBodies(partial.Data()))
+			for i := 0; i < partial.Data().ResourceLogs().Len(); i++ {
+				_, ok := partial.Data().ResourceLogs().At(i).Resource().Attributes().Get("prepared")
+				assert.False(t, ok, "retried records are taken from the request as received")
+			}
+		})
+	}
+}
+
+func TestExportTracesChunks(t *testing.T) {
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for k := 0; k < 5; k++ {
+		spans.AppendEmpty().SetName(fmt.Sprintf("span-%d", k))
+	}
+	plan, _ := planTracesByEvent(td, 2, 0, nil)
+	require.Equal(t, 3, plan.chunks())
+
+	chunk := 0
+	failed, err := exportTracesChunks(td, td, plan, func(i int, _ ptrace.Traces) error {
+		assert.Equal(t, chunk, i)
+		defer func() { chunk++ }()
+		if chunk == 2 {
+			return errors.New("encoding failed")
+		}
+		return nil
+	}, nil)
+	assert.Equal(t, 1, failed)
+	var partial consumererror.Traces
+	require.True(t, errors.As(err, &partial))
+	require.Equal(t, 1, partial.Data().SpanCount())
+	assert.Equal(t, "span-4", partial.Data().ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+}
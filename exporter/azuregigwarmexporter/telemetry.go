@@ -7,10 +7,13 @@ package azuregigwarmexporter
 
 import (
 	"context"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+
+	cgogeneva "github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter/internal/cgo"
 )
 
 // telemetry holds the metrics for the Azure GigWarm exporter
@@ -26,6 +29,26 @@ type telemetry struct {
 	logsExported        metric.Int64Counter
 	logsExportErrors    metric.Int64Counter
 	logsReceived        metric.Int64Counter
+	redactions          metric.Int64Counter
+	timestampActions    metric.Int64Counter
+	severityDecisions   metric.Int64Counter
+
+	// The Rust bridge gauges are observed by a single callback per process (see
+	// bridgeStats) while the exporter is running.
+	meter              metric.Meter
+	ffiLiveHandles     metric.Int64ObservableGauge
+	ffiBatchBytes      metric.Int64ObservableGauge
+	ffiClientsUploaded metric.Int64ObservableGauge
+	bridgeStatsStarted bool
+}
+
+// bridgeStats holds the process-wide registration of the Rust bridge gauges. The bridge
+// state is shared by all exporter instances, so the gauges are observed once, through the
+// telemetry of the first exporter that starts, for as long as any exporter is running.
+var bridgeStats struct {
+	sync.Mutex
+	refs int
+	reg  metric.Registration
 }
 
 // newTelemetry creates a new telemetry instance with Prometheus metrics
@@ -131,6 +154,60 @@ func newTelemetry(set component.TelemetrySettings) (*telemetry, error) {
 		return nil, err
 	}
 
+	redactions, err := meter.Int64Counter(
+		"azuregigwarm_exporter_redactions_total",
+		metric.WithDescription("Number of values redacted by the Azure GigWarm exporter before upload, per rule"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	timestampActions, err := meter.Int64Counter(
+		"azuregigwarm_exporter_timestamp_actions_total",
+		metric.WithDescription("Number of log records and spans whose timestamp was filled in, clamped or dropped by the Azure GigWarm exporter, per action"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	severityDecisions, err := meter.Int64Counter(
+		"azuregigwarm_exporter_severity_policy_logs_total",
+		metric.WithDescription("Number of log records kept or dropped by the Azure GigWarm exporter severity policy, per severity level"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ffiLiveHandles, err := meter.Int64ObservableGauge(
+		"azuregigwarm_exporter_ffi_live_handles",
+		metric.WithDescription("Number of live Rust bridge handles in the process, per handle type"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ffiBatchBytes, err := meter.Int64ObservableGauge(
+		"azuregigwarm_exporter_ffi_batch_bytes",
+		metric.WithDescription("Bytes held by encoded batches in the Rust bridge, in the process"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ffiClientsUploaded, err := meter.Int64ObservableGauge(
+		"azuregigwarm_exporter_ffi_clients_uploaded",
+		metric.WithDescription("Number of live Rust bridge clients in the process that have uploaded at least one batch"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &telemetry{
 		spansExported:       spansExported,
 		spansExportErrors:   spansExportErrors,
@@ -143,9 +220,60 @@ func newTelemetry(set component.TelemetrySettings) (*telemetry, error) {
 		logsExported:        logsExported,
 		logsExportErrors:    logsExportErrors,
 		logsReceived:        logsReceived,
+		redactions:          redactions,
+		timestampActions:    timestampActions,
+		severityDecisions:   severityDecisions,
+		meter:               meter,
+		ffiLiveHandles:      ffiLiveHandles,
+		ffiBatchBytes:       ffiBatchBytes,
+		ffiClientsUploaded:  ffiClientsUploaded,
 	}, nil
 }
 
+// startBridgeStats starts observing the Rust bridge gauges, unless another exporter in
+// the process already does.
+func (t *telemetry) startBridgeStats() error {
+	if t.bridgeStatsStarted {
+		return nil
+	}
+	bridgeStats.Lock()
+	defer bridgeStats.Unlock()
+	if bridgeStats.refs == 0 {
+		reg, err := t.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+			stats := cgogeneva.BridgeStats()
+			o.ObserveInt64(t.ffiLiveHandles, stats.LiveClients, metric.WithAttributes(attribute.String("handle", "client")))
+			o.ObserveInt64(t.ffiLiveHandles, stats.LiveBatches, metric.WithAttributes(attribute.String("handle", "batches")))
+			o.ObserveInt64(t.ffiBatchBytes, stats.BatchBytes)
+			o.ObserveInt64(t.ffiClientsUploaded, stats.ClientsUploaded)
+			return nil
+		}, t.ffiLiveHandles, t.ffiBatchBytes, t.ffiClientsUploaded)
+		if err != nil {
+			return err
+		}
+		bridgeStats.reg = reg
+	}
+	bridgeStats.refs++
+	t.bridgeStatsStarted = true
+	return nil
+}
+
+// stopBridgeStats stops observing the Rust bridge gauges once no exporter in the process
+// is running.
+func (t *telemetry) stopBridgeStats() error {
+	if !t.bridgeStatsStarted {
+		return nil
+	}
+	t.bridgeStatsStarted = false
+	bridgeStats.Lock()
+	defer bridgeStats.Unlock()
+	if bridgeStats.refs--; bridgeStats.refs > 0 {
+		return nil
+	}
+	err := bridgeStats.reg.Unregister()
+	bridgeStats.reg = nil
+	return err
+}
+
 // recordSpansExported records the number of spans successfully exported
 func (t *telemetry) recordSpansExported(ctx context.Context, count int64, attributes ...attribute.KeyValue) {
 	t.spansExported.Add(ctx, count, metric.WithAttributes(attributes...))
@@ -200,3 +328,28 @@ func (t *telemetry) recordLogsExported(ctx context.Context, count int64, attribu
 func (t *telemetry) recordLogsExportError(ctx context.Context, count int64, attributes ...attribute.KeyValue) {
 	t.logsExportErrors.Add(ctx, count, metric.WithAttributes(attributes...))
 }
+
+// recordRedactions records the number of redactions made by each rule
+func (t *telemetry) recordRedactions(ctx context.Context, counts redactionCounts, attributes ...attribute.KeyValue) {
+	for rule, count := range counts {
+		t.redactions.Add(ctx, count, metric.WithAttributes(append(attributes,
+			attribute.String("rule", rule))...))
+	}
+}
+
+// recordTimestampActions records the number of records per timestamp normalization action
+func (t *telemetry) recordTimestampActions(ctx context.Context, actions timestampActions, attributes ...attribute.KeyValue) {
+	for action, count := range actions {
+		t.timestampActions.Add(ctx, count, metric.WithAttributes(append(attributes,
+			attribute.String("action", action))...))
+	}
+}
+
+// recordSeverityDecisions records the number of log records kept and dropped per severity level
+func (t *telemetry) recordSeverityDecisions(ctx context.Context, decisions severityDecisions, attributes ...attribute.KeyValue) {
+	for key, count := range decisions {
+		t.severityDecisions.Add(ctx, count, metric.WithAttributes(append(attributes,
+			attribute.String("severity", key.level),
+			attribute.String("decision", key.decision))...))
+	}
+}
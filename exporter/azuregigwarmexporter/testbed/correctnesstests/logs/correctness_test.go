@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/testbed/testbed"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuregigwarmexporter/testbed/correctnesstests"
+)
+
+var correctnessResults testbed.TestResultsSummary = &testbed.CorrectnessResults{}
+
+func TestMain(m *testing.M) {
+	testbed.DoTestMain(m, correctnessResults)
+}
+
+// baseTime is the timestamp of the first generated record. It has sub-microsecond digits
+// so that truncation is noticed.
+var baseTime = time.Date(2026, 3, 14, 15, 9, 26, 535897900, time.UTC)
+
+// logsBuilder generates log records with unique record IDs.
+type logsBuilder struct {
+	ld     plog.Logs
+	nextID int64
+}
+
+func newLogsBuilder() *logsBuilder {
+	return &logsBuilder{ld: plog.NewLogs()}
+}
+
+// resource starts a new resource with a scope and returns the scope's record slice.
+func (b *logsBuilder) resource(attrs map[string]any) plog.LogRecordSlice {
+	rl := b.ld.ResourceLogs().AppendEmpty()
+	_ = rl.Resource().Attributes().FromRaw(attrs)
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("correctness")
+	sl.Scope().SetVersion("1.0.0")
+	return sl.LogRecords()
+}
+
+// record appends a record with an ID, timestamps and an INFO severity.
+func (b *logsBuilder) record(records plog.LogRecordSlice) plog.LogRecord {
+	lr := records.AppendEmpty()
+	ts := baseTime.Add(time.Duration(b.nextID) * time.Millisecond)
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(ts.Add(time.Second)))
+	lr.SetSeverityNumber(plog.SeverityNumberInfo)
+	lr.SetSeverityText("INFO")
+	lr.Attributes().PutInt(correctnesstests.RecordIDAttribute, b.nextID)
+	b.nextID++
+	return lr
+}
+
+var defaultResource = map[string]any{
+	"service.name":        "correctness",
+	"service.instance.id": "instance-1",
+	"host.name":           "testbed-host",
+}
+
+func TestLogsCorrectness(t *testing.T) {
+	tests := []struct {
+		name     string
+		generate func() plog.Logs
+	}{
+		{
+			name: "AllFields",
+			generate: func() plog.Logs {
+				b := newLogsBuilder()
+				lr := b.record(b.resource(defaultResource))
+				lr.Body().SetStr("user logged in")
+				lr.SetTraceID([16]byte{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0c})
+				lr.SetSpanID([8]byte{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x74})
+				lr.Attributes().PutStr("user.id", "u-123")
+				lr.Attributes().PutInt("http.status_code", 200)
+				lr.Attributes().PutDouble("duration_ms", 12.5)
+				lr.Attributes().PutBool("cached", true)
+				return b.ld
+			},
+		},
+		{
+			name: "AllSeverities",
+			generate: func() plog.Logs {
+				b := newLogsBuilder()
+				records := b.resource(defaultResource)
+				for n := plog.SeverityNumberUnspecified; n <= plog.SeverityNumberFatal4; n++ {
+					lr := b.record(records)
+					lr.SetSeverityNumber(n)
+					lr.SetSeverityText(n.String())
+					lr.Body().SetStr("severity " + n.String())
+				}
+				return b.ld
+			},
+		},
+		{
+			name: "EmptyBodies",
+			generate: func() plog.Logs {
+				b := newLogsBuilder()
+				records := b.resource(defaultResource)
+				b.record(records)
+				b.record(records).Body().SetStr("")
+				b.record(records).Body().SetEmptyMap()
+				return b.ld
+			},
+		},
+		{
+			name: "Unicode",
+			generate: func() plog.Logs {
+				b := newLogsBuilder()
+				records := b.resource(map[string]any{"service.name": "サービス", "host.name": "hôte-ü"})
+				for _, s := range []string{
+					"日本語のログメッセージ",
+					"emoji 🚀🔥 and ZWJ 👩‍💻",
+					"مرحبا بالعالم",
+					"combining é and é",
+					"control \t tab \n newline \u0000 nul",
+					"surrogate-free 𝄞 clef",
+				} {
+					lr := b.record(records)
+					lr.Body().SetStr(s)
+					lr.Attributes().PutStr("message.copy", s)
+				}
+				return b.ld
+			},
+		},
+		{
+			name: "LargeAttributes",
+			generate: func() plog.Logs {
+				b := newLogsBuilder()
+				records := b.resource(defaultResource)
+				lr := b.record(records)
+				lr.Body().SetStr(strings.Repeat("b", 256<<10))
+				lr.Attributes().PutStr("large", strings.Repeat("x", 64<<10))
+				lr = b.record(records)
+				for i := 0; i < 200; i++ {
+					lr.Attributes().PutStr("attr_"+strconv.Itoa(i), strings.Repeat("v", 10*i))
+				}
+				return b.ld
+			},
+		},
+		{
+			name: "NestedMaps",
+			generate: func() plog.Logs {
+				b := newLogsBuilder()
+				records := b.resource(defaultResource)
+				lr := b.record(records)
+				_ = lr.Body().SetEmptyMap().FromRaw(map[string]any{
+					"event": "checkout",
+					"cart": map[string]any{
+						"items": []any{
+							map[string]any{"sku": "A-1", "qty": int64(2), "price": 9.99},
+							map[string]any{"sku": "B-2", "qty": int64(1), "price": 19.5},
+						},
+						"coupon": nil,
+					},
+				})
+				_ = lr.Attributes().PutEmptyMap("request").FromRaw(map[string]any{
+					"headers": map[string]any{"accept": "application/json", "x-retry": []any{int64(1), int64(2)}},
+					"deep":    map[string]any{"a": map[string]any{"b": map[string]any{"c": "d"}}},
+				})
+				_ = lr.Attributes().PutEmptySlice("tags").FromRaw([]any{"a", int64(1), true, 2.5})
+				lr.Attributes().PutEmptyBytes("raw").FromRaw([]byte{0x00, 0x01, 0xfe, 0xff})
+				return b.ld
+			},
+		},
+		{
+			name: "ManyResources",
+			generate: func() plog.Logs {
+				b := newLogsBuilder()
+				for r := 0; r < 10; r++ {
+					records := b.resource(map[string]any{
+						"service.name":        "service-" + strconv.Itoa(r),
+						"service.instance.id": "instance-" + strconv.Itoa(r),
+					})
+					for i := 0; i < 100; i++ {
+						lr := b.record(records)
+						lr.Body().SetStr("record " + strconv.Itoa(i))
+						lr.Attributes().PutInt("resource.index", int64(r))
+					}
+				}
+				return b.ld
+			},
+		},
+		{
+			name: "RecordAttributeWinsOverResourceColumn",
+			generate: func() plog.Logs {
+				b := newLogsBuilder()
+				lr := b.record(b.resource(defaultResource))
+				lr.Attributes().PutStr("serviceName", "from-record")
+				return b.ld
+			},
+		},
+		{
+			name: "EventNames",
+			generate: func() plog.Logs {
+				b := newLogsBuilder()
+				records := b.resource(defaultResource)
+				b.record(records).SetEventName("AuditEvent")
+				b.record(records).SetEventName("")
+				return b.ld
+			},
+		},
+	}
+
+	env := correctnesstests.NewEnvironment(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := tt.generate()
+			received := env.ExportLogs(t, sent)
+			correctnesstests.AssertLogsEqual(t, sent, received)
+		})
+	}
+}
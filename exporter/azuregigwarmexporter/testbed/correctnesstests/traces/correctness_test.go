@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package traces
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/testbed/testbed"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuregigwarmexporter/testbed/correctnesstests"
+)
+
+var correctnessResults testbed.TestResultsSummary = &testbed.CorrectnessResults{}
+
+func TestMain(m *testing.M) {
+	testbed.DoTestMain(m, correctnessResults)
+}
+
+// baseTime is the start time of the first generated span. It has sub-microsecond digits
+// so that truncation is noticed.
+var baseTime = time.Date(2026, 3, 14, 15, 9, 26, 535897900, time.UTC)
+
+// tracesBuilder generates spans with unique span IDs.
+type tracesBuilder struct {
+	td     ptrace.Traces
+	nextID uint64
+}
+
+func newTracesBuilder() *tracesBuilder {
+	return &tracesBuilder{td: ptrace.NewTraces(), nextID: 1}
+}
+
+// resource starts a new resource with a scope and returns the scope's span slice.
+func (b *tracesBuilder) resource(attrs map[string]any) ptrace.SpanSlice {
+	rs := b.td.ResourceSpans().AppendEmpty()
+	_ = rs.Resource().Attributes().FromRaw(attrs)
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("correctness")
+	ss.Scope().SetVersion("1.0.0")
+	return ss.Spans()
+}
+
+// span appends a 25ms server span of trace traceID with a fresh span ID.
+func (b *tracesBuilder) span(spans ptrace.SpanSlice, traceID pcommon.TraceID, parent pcommon.SpanID) ptrace.Span {
+	span := spans.AppendEmpty()
+	var id pcommon.SpanID
+	binary.BigEndian.PutUint64(id[:], 0xa000000000000000|b.nextID)
+	span.SetSpanID(id)
+	span.SetTraceID(traceID)
+	span.SetParentSpanID(parent)
+	span.SetName("span-" + strconv.FormatUint(b.nextID, 10))
+	span.SetKind(ptrace.SpanKindServer)
+	start := baseTime.Add(time.Duration(b.nextID) * time.Millisecond)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(25*time.Millisecond + 123*time.Nanosecond)))
+	b.nextID++
+	return span
+}
+
+// newTraceID returns a trace ID derived from n.
+func newTraceID(n uint64) pcommon.TraceID {
+	var id pcommon.TraceID
+	binary.BigEndian.PutUint64(id[:8], 0x5b8efff798038103)
+	binary.BigEndian.PutUint64(id[8:], n)
+	return id
+}
+
+var defaultResource = map[string]any{
+	"service.name":        "correctness",
+	"service.instance.id": "instance-1",
+	"host.name":           "testbed-host",
+}
+
+func TestTracesCorrectness(t *testing.T) {
+	tests := []struct {
+		name     string
+		generate func() ptrace.Traces
+	}{
+		{
+			name: "AllKinds",
+			generate: func() ptrace.Traces {
+				b := newTracesBuilder()
+				spans := b.resource(defaultResource)
+				root := b.span(spans, newTraceID(1), pcommon.SpanID{})
+				for kind := ptrace.SpanKindUnspecified; kind <= ptrace.SpanKindConsumer; kind++ {
+					span := b.span(spans, newTraceID(1), root.SpanID())
+					span.SetKind(kind)
+					span.SetName("kind " + kind.String())
+				}
+				return b.td
+			},
+		},
+		{
+			name: "AttributeTypes",
+			generate: func() ptrace.Traces {
+				b := newTracesBuilder()
+				span := b.span(b.resource(defaultResource), newTraceID(2), pcommon.SpanID{})
+				span.Attributes().PutStr("http.request.method", "GET")
+				span.Attributes().PutStr("url.full", "https://example.com/a?b=c")
+				span.Attributes().PutInt("retry.count", -3)
+				span.Attributes().PutDouble("ratio", 0.125)
+				span.Attributes().PutBool("cache.hit", false)
+				return b.td
+			},
+		},
+		{
+			name: "Status",
+			generate: func() ptrace.Traces {
+				b := newTracesBuilder()
+				spans := b.resource(defaultResource)
+				b.span(spans, newTraceID(3), pcommon.SpanID{}).Status().SetCode(ptrace.StatusCodeOk)
+				failed := b.span(spans, newTraceID(3), pcommon.SpanID{})
+				failed.Status().SetCode(ptrace.StatusCodeError)
+				failed.Status().SetMessage("upstream timed out")
+				b.span(spans, newTraceID(3), pcommon.SpanID{})
+				return b.td
+			},
+		},
+		{
+			name: "ZeroDuration",
+			generate: func() ptrace.Traces {
+				b := newTracesBuilder()
+				span := b.span(b.resource(defaultResource), newTraceID(4), pcommon.SpanID{})
+				span.SetEndTimestamp(span.StartTimestamp())
+				return b.td
+			},
+		},
+		{
+			name: "Unicode",
+			generate: func() ptrace.Traces {
+				b := newTracesBuilder()
+				spans := b.resource(map[string]any{"service.name": "サービス", "host.name": "hôte-ü"})
+				for _, s := range []string{
+					"GET /ユーザー/{id}",
+					"emoji 🚀🔥 and ZWJ 👩‍💻",
+					"مرحبا بالعالم",
+					"combining é and é",
+					"𝄞 clef",
+				} {
+					span := b.span(spans, newTraceID(5), pcommon.SpanID{})
+					span.SetName(s)
+					span.Attributes().PutStr("name.copy", s)
+				}
+				return b.td
+			},
+		},
+		{
+			name: "LargeAttributes",
+			generate: func() ptrace.Traces {
+				b := newTracesBuilder()
+				spans := b.resource(defaultResource)
+				span := b.span(spans, newTraceID(6), pcommon.SpanID{})
+				span.SetName(strings.Repeat("n", 4<<10))
+				span.Attributes().PutStr("db.query.text", strings.Repeat("SELECT 1; ", 16<<10))
+				span = b.span(spans, newTraceID(6), pcommon.SpanID{})
+				for i := 0; i < 200; i++ {
+					span.Attributes().PutStr("attr_"+strconv.Itoa(i), strings.Repeat("v", 10*i))
+				}
+				return b.td
+			},
+		},
+		{
+			name: "NestedMaps",
+			generate: func() ptrace.Traces {
+				b := newTracesBuilder()
+				span := b.span(b.resource(defaultResource), newTraceID(7), pcommon.SpanID{})
+				_ = span.Attributes().PutEmptyMap("request").FromRaw(map[string]any{
+					"headers": map[string]any{"accept": "application/json", "x-retry": []any{int64(1), int64(2)}},
+					"deep":    map[string]any{"a": map[string]any{"b": map[string]any{"c": "d"}}},
+				})
+				_ = span.Attributes().PutEmptySlice("tags").FromRaw([]any{"a", int64(1), true, 2.5})
+				span.Attributes().PutEmptyBytes("raw").FromRaw([]byte{0x00, 0x01, 0xfe, 0xff})
+				return b.td
+			},
+		},
+		{
+			name: "ManyResources",
+			generate: func() ptrace.Traces {
+				b := newTracesBuilder()
+				for r := 0; r < 10; r++ {
+					spans := b.resource(map[string]any{
+						"service.name":        "service-" + strconv.Itoa(r),
+						"service.instance.id": "instance-" + strconv.Itoa(r),
+					})
+					root := b.span(spans, newTraceID(uint64(100+r)), pcommon.SpanID{})
+					for i := 0; i < 100; i++ {
+						b.span(spans, root.TraceID(), root.SpanID()).Attributes().PutInt("resource.index", int64(r))
+					}
+				}
+				return b.td
+			},
+		},
+	}
+
+	env := correctnesstests.NewEnvironment(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := tt.generate()
+			received := env.ExportTraces(t, sent)
+			correctnesstests.AssertTracesEqual(t, sent, received)
+		})
+	}
+}
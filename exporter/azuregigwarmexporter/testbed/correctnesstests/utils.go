@@ -0,0 +1,319 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package correctnesstests contains helpers for the correctness tests of the azuregigwarm
+// exporter. The tests run the exporter in-process against the Geneva emulator, decode the
+// uploaded batches and compare them field by field with the data that was exported.
+package correctnesstests // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuregigwarmexporter/testbed/correctnesstests"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+	"github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter"
+
+	"go.opentelemetry.io/collector/testbed/datareceivers"
+)
+
+// RecordIDAttribute identifies a generated log record, so that sent and received records
+// can be matched.
+const RecordIDAttribute = "test.record_id"
+
+// ResourceColumns are the resource attributes the exporter is configured to promote to
+// columns, keyed by attribute. Promoted values are expected as record attributes named
+// after the column.
+var ResourceColumns = map[string]string{
+	"service.name":        "serviceName",
+	"service.instance.id": "serviceInstanceId",
+	"host.name":           "hostName",
+}
+
+// TimestampPrecision is the precision timestamps are compared with. Geneva stores times
+// in 100 ns ticks.
+const TimestampPrecision = 100
+
+// Environment is a running exporter and decoding receiver.
+type Environment struct {
+	Receiver *datareceivers.AzureGigWarmDataReceiver
+	Logs     *consumertest.LogsSink
+	Traces   *consumertest.TracesSink
+
+	logsExporter   exporter.Logs
+	tracesExporter exporter.Traces
+}
+
+// NewEnvironment starts the decoding receiver and a logs and a traces exporter that
+// upload to it. The exporters run without a queue or retries, so a consume call returns
+// after its batches were received.
+func NewEnvironment(t *testing.T) *Environment {
+	t.Helper()
+	env := &Environment{
+		Receiver: datareceivers.NewAzureGigWarmDataReceiver(testutil.GetAvailablePort(t)).(*datareceivers.AzureGigWarmDataReceiver),
+		Logs:     new(consumertest.LogsSink),
+		Traces:   new(consumertest.TracesSink),
+	}
+	require.NoError(t, env.Receiver.Start(env.Traces, nil, env.Logs))
+	t.Cleanup(func() { assert.NoError(t, env.Receiver.Stop()) })
+
+	ctx := context.Background()
+	factory := azuregigwarmexporter.NewFactory()
+	set := exportertest.NewNopSettings(azuregigwarmexporter.Type)
+
+	var err error
+	env.logsExporter, err = factory.CreateLogs(ctx, set, newExporterConfig(factory, env.Receiver))
+	require.NoError(t, err)
+	require.NoError(t, env.logsExporter.Start(ctx, componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, env.logsExporter.Shutdown(context.Background())) })
+
+	env.tracesExporter, err = factory.CreateTraces(ctx, set, newExporterConfig(factory, env.Receiver))
+	require.NoError(t, err)
+	require.NoError(t, env.tracesExporter.Start(ctx, componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, env.tracesExporter.Shutdown(context.Background())) })
+	return env
+}
+
+// newExporterConfig returns an exporter config that uploads to the receiver's emulator.
+func newExporterConfig(factory exporter.Factory, receiver *datareceivers.AzureGigWarmDataReceiver) *azuregigwarmexporter.Config {
+	cfg := factory.CreateDefaultConfig().(*azuregigwarmexporter.Config)
+	cfg.Endpoint = fmt.Sprintf("http://127.0.0.1:%d", receiver.Port)
+	cfg.Environment = "correctness"
+	cfg.Account = "testbed"
+	cfg.Namespace = "correctness"
+	cfg.Region = "local"
+	cfg.ConfigMajorVersion = 1
+	cfg.Tenant = "test-tenant"
+	cfg.RoleName = "testbed-role"
+	cfg.RoleInstance = "instance-01"
+	cfg.QueueConfig.Enabled = false
+	cfg.RetryConfig.Enabled = false
+	for attr, column := range ResourceColumns {
+		cfg.Schema.ResourceColumns = append(cfg.Schema.ResourceColumns, azuregigwarmexporter.ColumnMapping{Attribute: attr, Column: column})
+	}
+	return cfg
+}
+
+// ExportLogs exports ld and returns the log records the receiver decoded from the uploads.
+// The exporter mutates its input, so a copy of ld is exported.
+func (env *Environment) ExportLogs(t *testing.T, ld plog.Logs) plog.Logs {
+	t.Helper()
+	env.Logs.Reset()
+	sent := plog.NewLogs()
+	ld.CopyTo(sent)
+	require.NoError(t, env.logsExporter.ConsumeLogs(context.Background(), sent))
+	require.Zero(t, env.Receiver.DecodeErrors(), "uploads failed to decode")
+	require.Zero(t, env.Receiver.ConsumeErrors(), "decoded uploads were not forwarded")
+
+	received := plog.NewLogs()
+	for _, l := range env.Logs.AllLogs() {
+		l.ResourceLogs().MoveAndAppendTo(received.ResourceLogs())
+	}
+	return received
+}
+
+// ExportTraces exports td and returns the spans the receiver decoded from the uploads.
+// The exporter mutates its input, so a copy of td is exported.
+func (env *Environment) ExportTraces(t *testing.T, td ptrace.Traces) ptrace.Traces {
+	t.Helper()
+	env.Traces.Reset()
+	sent := ptrace.NewTraces()
+	td.CopyTo(sent)
+	require.NoError(t, env.tracesExporter.ConsumeTraces(context.Background(), sent))
+	require.Zero(t, env.Receiver.DecodeErrors(), "uploads failed to decode")
+	require.Zero(t, env.Receiver.ConsumeErrors(), "decoded uploads were not forwarded")
+
+	received := ptrace.NewTraces()
+	for _, tr := range env.Traces.AllTraces() {
+		tr.ResourceSpans().MoveAndAppendTo(received.ResourceSpans())
+	}
+	return received
+}
+
+// AssertLogsEqual checks that every sent log record was received exactly once, with the
+// same fields. Records are matched by RecordIDAttribute.
+func AssertLogsEqual(t *testing.T, sent, received plog.Logs) {
+	t.Helper()
+	got := make(map[int64]plog.LogRecord)
+	forEachLogRecord(received, func(_ pcommon.Resource, lr plog.LogRecord) {
+		id, ok := lr.Attributes().Get(RecordIDAttribute)
+		if !assert.True(t, ok, "received log record without %q", RecordIDAttribute) {
+			return
+		}
+		_, dup := got[id.Int()]
+		assert.False(t, dup, "log record %d received more than once", id.Int())
+		got[id.Int()] = lr
+	})
+	require.Equal(t, sent.LogRecordCount(), len(got), "number of log records")
+
+	forEachLogRecord(sent, func(res pcommon.Resource, want plog.LogRecord) {
+		id, _ := want.Attributes().Get(RecordIDAttribute)
+		lr, ok := got[id.Int()]
+		if !assert.True(t, ok, "log record %d was not received", id.Int()) {
+			return
+		}
+		path := fmt.Sprintf("log record %d", id.Int())
+		assertTimestamp(t, path+": timestamp", want.Timestamp(), lr.Timestamp())
+		assert.Equal(t, want.SeverityNumber(), lr.SeverityNumber(), "%s: severity number", path)
+		assert.Equal(t, want.SeverityText(), lr.SeverityText(), "%s: severity text", path)
+		assert.Equal(t, want.TraceID(), lr.TraceID(), "%s: trace id", path)
+		assert.Equal(t, want.SpanID(), lr.SpanID(), "%s: span id", path)
+		assert.Equal(t, want.EventName(), lr.EventName(), "%s: event name", path)
+		if isEmptyValue(want.Body()) {
+			assert.True(t, isEmptyValue(lr.Body()) || lr.Body().AsString() == "{}", "%s: body %q is not empty", path, lr.Body().AsString())
+		} else {
+			AssertValueEqual(t, path+": body", want.Body(), lr.Body())
+		}
+		AssertAttributesEqual(t, path, res.Attributes(), want.Attributes(), lr.Attributes())
+	})
+}
+
+// AssertTracesEqual checks that every sent span was received exactly once, with the same
+// fields. Spans are matched by span ID.
+func AssertTracesEqual(t *testing.T, sent, received ptrace.Traces) {
+	t.Helper()
+	got := make(map[pcommon.SpanID]ptrace.Span)
+	forEachSpan(received, func(_ pcommon.Resource, span ptrace.Span) {
+		_, dup := got[span.SpanID()]
+		assert.False(t, dup, "span %s received more than once", span.SpanID())
+		got[span.SpanID()] = span
+	})
+	require.Equal(t, sent.SpanCount(), len(got), "number of spans")
+
+	forEachSpan(sent, func(res pcommon.Resource, want ptrace.Span) {
+		span, ok := got[want.SpanID()]
+		if !assert.True(t, ok, "span %s was not received", want.SpanID()) {
+			return
+		}
+		path := "span " + want.SpanID().String()
+		assert.Equal(t, want.Name(), span.Name(), "%s: name", path)
+		assert.Equal(t, want.Kind(), span.Kind(), "%s: kind", path)
+		assert.Equal(t, want.TraceID(), span.TraceID(), "%s: trace id", path)
+		assert.Equal(t, want.ParentSpanID(), span.ParentSpanID(), "%s: parent span id", path)
+		assertTimestamp(t, path+": start", want.StartTimestamp(), span.StartTimestamp())
+		assertTimestamp(t, path+": end", want.EndTimestamp(), span.EndTimestamp())
+		assert.Equal(t, want.Status().Code() == ptrace.StatusCodeError, span.Status().Code() == ptrace.StatusCodeError,
+			"%s: error status", path)
+		AssertAttributesEqual(t, path, res.Attributes(), want.Attributes(), span.Attributes())
+	})
+}
+
+// AssertAttributesEqual checks that got holds exactly the attributes of want and the
+// promoted ResourceColumns of resource. Attributes the exporter derives from other fields
+// (such as the HTTP status code of spans) are only checked if they were sent.
+func AssertAttributesEqual(t *testing.T, path string, resource, want, got pcommon.Map) {
+	t.Helper()
+	expected := pcommon.NewMap()
+	want.CopyTo(expected)
+	for attr, column := range ResourceColumns {
+		if v, ok := resource.Get(attr); ok {
+			if _, set := expected.Get(column); !set {
+				v.CopyTo(expected.PutEmpty(column))
+			}
+		}
+	}
+
+	expected.Range(func(k string, v pcommon.Value) bool {
+		gv, ok := got.Get(k)
+		if assert.True(t, ok, "%s: attribute %q is missing", path, k) {
+			AssertValueEqual(t, fmt.Sprintf("%s: attribute %q", path, k), v, gv)
+		}
+		return true
+	})
+	got.Range(func(k string, _ pcommon.Value) bool {
+		_, ok := expected.Get(k)
+		assert.True(t, ok || k == "http.response.status_code", "%s: unexpected attribute %q", path, k)
+		return true
+	})
+}
+
+// AssertValueEqual compares two values by content. Structured values may be encoded as
+// JSON strings, and numbers as any numeric type, so both sides are compared in their
+// JSON form.
+func AssertValueEqual(t *testing.T, path string, want, got pcommon.Value) {
+	t.Helper()
+	wantJSON := normalizeJSON(t, want.AsRaw())
+	var gotJSON any
+	switch {
+	case got.Type() == pcommon.ValueTypeStr && (want.Type() == pcommon.ValueTypeMap || want.Type() == pcommon.ValueTypeSlice):
+		if !assert.NoError(t, json.Unmarshal([]byte(got.Str()), &gotJSON), "%s: structured value is not JSON: %q", path, got.Str()) {
+			return
+		}
+	case got.Type() == pcommon.ValueTypeStr && want.Type() == pcommon.ValueTypeBytes:
+		gotJSON = got.Str()
+		wantJSON = want.AsString()
+	default:
+		gotJSON = normalizeJSON(t, got.AsRaw())
+	}
+	if !reflect.DeepEqual(wantJSON, gotJSON) {
+		assert.Fail(t, fmt.Sprintf("%s differs", path), "want %#v\ngot  %#v", wantJSON, gotJSON)
+	}
+}
+
+// isEmptyValue reports whether v is unset, an empty string or an empty map or slice.
+func isEmptyValue(v pcommon.Value) bool {
+	switch v.Type() {
+	case pcommon.ValueTypeEmpty:
+		return true
+	case pcommon.ValueTypeStr:
+		return v.Str() == ""
+	case pcommon.ValueTypeMap:
+		return v.Map().Len() == 0
+	case pcommon.ValueTypeSlice:
+		return v.Slice().Len() == 0
+	default:
+		return false
+	}
+}
+
+// normalizeJSON round-trips v through JSON, so that equal values compare equal
+// regardless of their Go types.
+func normalizeJSON(t *testing.T, v any) any {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	var out any
+	require.NoError(t, json.Unmarshal(b, &out))
+	return out
+}
+
+// assertTimestamp compares timestamps at TimestampPrecision.
+func assertTimestamp(t *testing.T, path string, want, got pcommon.Timestamp) {
+	t.Helper()
+	assert.Equal(t, uint64(want)/TimestampPrecision, uint64(got)/TimestampPrecision, "%s: want %s, got %s", path, want, got)
+}
+
+func forEachLogRecord(ld plog.Logs, f func(pcommon.Resource, plog.LogRecord)) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				f(rl.Resource(), records.At(k))
+			}
+		}
+	}
+}
+
+func forEachSpan(td ptrace.Traces, f func(pcommon.Resource, ptrace.Span)) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				f(rs.Resource(), spans.At(k))
+			}
+		}
+	}
+}
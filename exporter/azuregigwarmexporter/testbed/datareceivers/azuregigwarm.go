@@ -6,28 +6,36 @@ package datareceivers // import "go.opentelemetry.io/collector/testbed/datarecei
 import (
 	"context"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
 	"sync/atomic"
 
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/pdata/plog"
-	"go.opentelemetry.io/collector/pdata/ptrace"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuregigwarmexporter/testbed/genevaemulator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/testbed/testbed"
 )
 
 // AzureGigWarmDataReceiver implements a mock Azure Geneva receiver for load testing.
-// This is a simplified HTTP endpoint that mimics the Geneva backend to enable
-// load testing of the azuregigwarmexporter without requiring actual Azure credentials
-// or connectivity.
+// It runs a local Geneva emulator (config service and ingestion gateway), so the exporter
+// goes through the real GCS and upload flow without Azure credentials or connectivity.
+// The exporter's Rust bridge must be built with the mock_auth Cargo feature.
+//
+// Every accepted upload is decompressed and decoded. The decoded spans and log records are
+// counted and forwarded, reconstructed as pdata, to the consumers passed to Start, so the
+// testbed load generator and validators compare what was sent with what Geneva received.
 type AzureGigWarmDataReceiver struct {
 	testbed.DataReceiverBase
-	server         *http.Server
+	emulator       *genevaemulator.Emulator
+	faults         genevaemulator.FaultProfile
+	retry          string
+	queue          string
+	nextTraces     consumer.Traces
+	nextLogs       consumer.Logs
 	tracesReceived atomic.Uint64
 	logsReceived   atomic.Uint64
 	bytesReceived  atomic.Uint64
+	uploads        atomic.Uint64
+	decodeErrors   atomic.Uint64
+	consumeErrors  atomic.Uint64
 }
 
 // NewAzureGigWarmDataReceiver creates a new mock Azure GigWarm receiver
@@ -37,74 +45,89 @@ func NewAzureGigWarmDataReceiver(port int) testbed.DataReceiver {
 	}
 }
 
-// Start starts the mock receiver HTTP server
-func (r *AzureGigWarmDataReceiver) Start(tc consumer.Traces, _ consumer.Metrics, lc consumer.Logs) error {
-	mux := http.NewServeMux()
-
-	// Mock endpoint for receiving telemetry from GigWarm exporter
-	// In reality, Geneva uses a more complex protocol, but for load testing
-	// we just need to accept the data and track metrics
-	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
-		if req.Method != http.MethodPost {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-
-		// Read and count bytes
-		bodyBytes, err := io.ReadAll(req.Body)
-		if err != nil {
-			log.Printf("Failed to read request body: %v", err)
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
-		defer req.Body.Close()
-
-		r.bytesReceived.Add(uint64(len(bodyBytes)))
+// WithFaultProfile makes the Geneva emulator inject the faults of p into requests.
+func (r *AzureGigWarmDataReceiver) WithFaultProfile(p genevaemulator.FaultProfile) *AzureGigWarmDataReceiver {
+	r.faults = p
+	return r
+}
 
-		// Try to determine if it's traces or logs based on content-type or path
-		contentType := req.Header.Get("Content-Type")
-		if contentType == "" {
-			contentType = "application/octet-stream"
-		}
+// WithRetry sets the exporter retry_on_failure config body
+func (r *AzureGigWarmDataReceiver) WithRetry(retry string) *AzureGigWarmDataReceiver {
+	r.retry = retry
+	return r
+}
 
-		// For simplicity, we'll just count requests as either traces or logs
-		// In a real implementation, you would parse the Geneva protocol
-		if req.URL.Path == "/traces" || req.Header.Get("X-Telemetry-Type") == "traces" {
-			r.tracesReceived.Add(1)
-		} else {
-			r.logsReceived.Add(1)
-		}
+// WithQueue sets the exporter sending_queue config body
+func (r *AzureGigWarmDataReceiver) WithQueue(queue string) *AzureGigWarmDataReceiver {
+	r.queue = queue
+	return r
+}
 
-		// Return success
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
+// Start starts the Geneva emulator. Decoded spans and log records are forwarded to tc and lc.
+func (r *AzureGigWarmDataReceiver) Start(tc consumer.Traces, _ consumer.Metrics, lc consumer.Logs) error {
+	r.nextTraces = tc
+	r.nextLogs = lc
+	r.emulator = genevaemulator.New(genevaemulator.Config{
+		Endpoint: fmt.Sprintf("127.0.0.1:%d", r.Port),
+		OnUpload: r.onUpload,
+		// Uploads are decoded and counted by onUpload, so the emulator keeps none
+		RetainUploads: -1,
+		Faults:        r.faults,
 	})
-
-	r.server = &http.Server{
-		Addr:    fmt.Sprintf("127.0.0.1:%d", r.Port),
-		Handler: mux,
+	if err := r.emulator.Start(); err != nil {
+		return fmt.Errorf("failed to start Geneva emulator: %w", err)
 	}
+	return nil
+}
 
-	go func() {
-		if err := r.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("Mock receiver server error: %v", err)
+// onUpload decodes an upload accepted by the emulator, counts its spans and log records
+// and forwards them. Uploads or rows that fail to decode are counted as decode errors, and
+// uploads the next consumer rejects as consume errors.
+func (r *AzureGigWarmDataReceiver) onUpload(upload genevaemulator.Upload) {
+	r.uploads.Add(1)
+	r.bytesReceived.Add(uint64(len(upload.Body)))
+	decoded, err := genevaemulator.DecodeUpload(upload)
+	if err != nil {
+		r.decodeErrors.Add(1)
+		return
+	}
+	r.decodeErrors.Add(uint64(decoded.RowErrors))
+
+	ctx := context.Background()
+	if n := decoded.Traces.SpanCount(); n > 0 {
+		r.tracesReceived.Add(uint64(n))
+		if r.nextTraces != nil {
+			if err := r.nextTraces.ConsumeTraces(ctx, decoded.Traces); err != nil {
+				r.consumeErrors.Add(1)
+			}
 		}
-	}()
-
-	return nil
+	}
+	if n := decoded.Logs.LogRecordCount(); n > 0 {
+		r.logsReceived.Add(uint64(n))
+		if r.nextLogs != nil {
+			if err := r.nextLogs.ConsumeLogs(ctx, decoded.Logs); err != nil {
+				r.consumeErrors.Add(1)
+			}
+		}
+	}
 }
 
-// Stop stops the mock receiver
+// Stop stops the Geneva emulator
 func (r *AzureGigWarmDataReceiver) Stop() error {
-	if r.server != nil {
-		return r.server.Shutdown(context.Background())
+	if r.emulator != nil {
+		return r.emulator.Stop(context.Background())
 	}
 	return nil
 }
 
+// Emulator returns the Geneva emulator, or nil before Start
+func (r *AzureGigWarmDataReceiver) Emulator() *genevaemulator.Emulator {
+	return r.emulator
+}
+
 // GenConfigYAMLStr returns collector config for the azuregigwarm exporter
 func (r *AzureGigWarmDataReceiver) GenConfigYAMLStr() string {
-	// Note: This creates a config that points to our mock endpoint
+	// Note: This creates a config that points to the Geneva emulator
 	// For real Geneva testing, you would use actual credentials and endpoints
 	return fmt.Sprintf(`
   azuregigwarm:
@@ -118,7 +141,7 @@ func (r *AzureGigWarmDataReceiver) GenConfigYAMLStr() string {
     tenant: test-tenant
     role_name: testbed-role
     role_instance: instance-01
-`, r.Port)
+`, r.Port) + r.retry + r.queue
 }
 
 // ProtocolName returns the protocol name
@@ -126,16 +149,31 @@ func (r *AzureGigWarmDataReceiver) ProtocolName() string {
 	return "azuregigwarm"
 }
 
-// ReceivedTraces returns the received traces count
+// ReceivedTraces returns the number of decoded spans
 func (r *AzureGigWarmDataReceiver) ReceivedTraces() uint64 {
 	return r.tracesReceived.Load()
 }
 
-// ReceivedLogs returns the received logs count
+// ReceivedLogs returns the number of decoded log records
 func (r *AzureGigWarmDataReceiver) ReceivedLogs() uint64 {
 	return r.logsReceived.Load()
 }
 
+// ReceivedUploads returns the number of accepted uploads
+func (r *AzureGigWarmDataReceiver) ReceivedUploads() uint64 {
+	return r.uploads.Load()
+}
+
+// DecodeErrors returns the number of uploads and rows that could not be decoded
+func (r *AzureGigWarmDataReceiver) DecodeErrors() uint64 {
+	return r.decodeErrors.Load()
+}
+
+// ConsumeErrors returns the number of decoded uploads the next consumer failed to accept
+func (r *AzureGigWarmDataReceiver) ConsumeErrors() uint64 {
+	return r.consumeErrors.Load()
+}
+
 // ReceivedBytes returns the total bytes received
 func (r *AzureGigWarmDataReceiver) ReceivedBytes() uint64 {
 	return r.bytesReceived.Load()
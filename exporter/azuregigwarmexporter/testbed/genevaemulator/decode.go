@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genevaemulator // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuregigwarmexporter/testbed/genevaemulator"
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter/genevapayload"
+)
+
+// SpanEventName is the Geneva event the exporter writes spans to. Rows of every other
+// event are log records.
+const SpanEventName = "Span"
+
+// Columns written by the encoder. Columns that are not listed here become attributes of
+// the reconstructed record, except for other envelope ("env_") columns, which are dropped.
+const (
+	colTimestamp     = "timestamp"
+	colEnvTime       = "env_time"
+	colTraceID       = "env_dt_traceId"
+	colSpanID        = "env_dt_spanId"
+	colTraceFlags    = "env_dt_traceFlags"
+	colName          = "name"
+	colSeverityNum   = "SeverityNumber"
+	colSeverityText  = "SeverityText"
+	colBody          = "body"
+	colParentID      = "parentId"
+	colKind          = "kind"
+	colStartTime     = "startTime"
+	colSuccess       = "success"
+	colHTTPStatus    = "httpStatusCode"
+	colLinks         = "links"
+	colStatusMessage = "statusMessage"
+
+	envelopePrefix = "env_"
+)
+
+// Decoded is the content of one upload, reconstructed as pdata.
+type Decoded struct {
+	Logs   plog.Logs
+	Traces ptrace.Traces
+	// RowErrors is the number of rows that could not be decoded. They are not part of
+	// Logs or Traces.
+	RowErrors int
+}
+
+// DecodeUpload decompresses and decodes the rows of an upload and reconstructs them as
+// log records or spans, depending on the event they were written to.
+//
+// The reconstruction is lossy: resource and scope information is not part of the Geneva
+// schema, so all records end up under one resource and scope, and span events and links
+// are not restored.
+func DecodeUpload(u Upload) (Decoded, error) {
+	out := Decoded{Logs: plog.NewLogs(), Traces: ptrace.NewTraces()}
+	b, err := genevapayload.Decode(u.Body)
+	if err != nil {
+		return out, err
+	}
+	records := out.Logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	spans := out.Traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for _, row := range b.Rows() {
+		switch {
+		case row.Err != nil:
+			out.RowErrors++
+		case row.Event == SpanEventName:
+			rowToSpan(row, spans.AppendEmpty())
+		default:
+			rowToLogRecord(row, records.AppendEmpty())
+		}
+	}
+	if records.Len() == 0 {
+		out.Logs = plog.NewLogs()
+	}
+	if spans.Len() == 0 {
+		out.Traces = ptrace.NewTraces()
+	}
+	return out, nil
+}
+
+// rowToLogRecord fills lr from a decoded log row.
+func rowToLogRecord(row genevapayload.Row, lr plog.LogRecord) {
+	if row.Event != "" && row.Event != "Log" {
+		lr.SetEventName(row.Event)
+	}
+	for _, c := range row.Columns {
+		switch c.Name {
+		case colName:
+		case colTimestamp:
+			lr.SetTimestamp(toTimestamp(c.Value))
+		case colEnvTime:
+			lr.SetObservedTimestamp(toTimestamp(c.Value))
+		case colTraceID:
+			lr.SetTraceID(toTraceID(c.Value))
+		case colSpanID:
+			lr.SetSpanID(toSpanID(c.Value))
+		case colTraceFlags:
+			lr.SetFlags(plog.LogRecordFlags(toInt(c.Value)))
+		case colSeverityNum:
+			lr.SetSeverityNumber(plog.SeverityNumber(toInt(c.Value)))
+		case colSeverityText:
+			lr.SetSeverityText(fmt.Sprint(c.Value))
+		case colBody:
+			putValue(lr.Body(), c.Value)
+		default:
+			if !strings.HasPrefix(c.Name, envelopePrefix) {
+				putValue(lr.Attributes().PutEmpty(c.Name), c.Value)
+			}
+		}
+	}
+}
+
+// rowToSpan fills span from a decoded span row.
+func rowToSpan(row genevapayload.Row, span ptrace.Span) {
+	for _, c := range row.Columns {
+		switch c.Name {
+		case colEnvTime, colLinks:
+		case colTimestamp:
+			span.SetEndTimestamp(toTimestamp(c.Value))
+		case colStartTime:
+			span.SetStartTimestamp(toTimestamp(c.Value))
+		case colTraceID:
+			span.SetTraceID(toTraceID(c.Value))
+		case colSpanID:
+			span.SetSpanID(toSpanID(c.Value))
+		case colParentID:
+			span.SetParentSpanID(toSpanID(c.Value))
+		case colTraceFlags:
+			span.SetFlags(uint32(toInt(c.Value)))
+		case colName:
+			span.SetName(fmt.Sprint(c.Value))
+		case colKind:
+			span.SetKind(ptrace.SpanKind(toInt(c.Value)))
+		case colSuccess:
+			if ok, isBool := c.Value.(bool); isBool && !ok {
+				span.Status().SetCode(ptrace.StatusCodeError)
+			}
+		case colStatusMessage:
+			span.Status().SetMessage(fmt.Sprint(c.Value))
+		case colHTTPStatus:
+			span.Attributes().PutInt("http.response.status_code", toInt(c.Value))
+		default:
+			if !strings.HasPrefix(c.Name, envelopePrefix) {
+				putValue(span.Attributes().PutEmpty(c.Name), c.Value)
+			}
+		}
+	}
+}
+
+// toTimestamp converts a timestamp column, either an RFC 3339 string or Unix nanoseconds.
+func toTimestamp(v any) pcommon.Timestamp {
+	if s, ok := v.(string); ok {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return 0
+		}
+		return pcommon.NewTimestampFromTime(t)
+	}
+	return pcommon.Timestamp(toInt(v))
+}
+
+// toTraceID decodes a hex encoded trace ID. Invalid IDs decode to the empty ID.
+func toTraceID(v any) pcommon.TraceID {
+	var id pcommon.TraceID
+	copy(id[:], hexBytes(v, len(id)))
+	return id
+}
+
+// toSpanID decodes a hex encoded span ID. Invalid IDs decode to the empty ID.
+func toSpanID(v any) pcommon.SpanID {
+	var id pcommon.SpanID
+	copy(id[:], hexBytes(v, len(id)))
+	return id
+}
+
+// hexBytes decodes a hex string of n bytes, or returns nil.
+func hexBytes(v any, n int) []byte {
+	s, _ := v.(string)
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != n {
+		return nil
+	}
+	return b
+}
+
+// toInt converts an integer column of any width.
+func toInt(v any) int64 {
+	switch v := v.(type) {
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+// putValue stores a decoded column value in dst. Values of other types are stored as
+// their JSON encoding.
+func putValue(dst pcommon.Value, v any) {
+	switch v := v.(type) {
+	case string:
+		dst.SetStr(v)
+	case bool:
+		dst.SetBool(v)
+	case float32:
+		dst.SetDouble(float64(v))
+	case float64:
+		dst.SetDouble(v)
+	case int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+		dst.SetInt(toInt(v))
+	case []any:
+		_ = dst.SetEmptySlice().FromRaw(v)
+	case map[string]any:
+		_ = dst.SetEmptyMap().FromRaw(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			dst.SetStr(fmt.Sprint(v))
+			return
+		}
+		dst.SetStr(string(b))
+	}
+}
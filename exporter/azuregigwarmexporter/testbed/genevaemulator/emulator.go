@@ -0,0 +1,370 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package genevaemulator implements a local Geneva backend for end-to-end tests of the
+// azuregigwarm exporter. It serves the two endpoints the Rust uploader talks to:
+//
+//   - the Geneva Config Service (GCS) MonitoringStorageKeys endpoint, which returns the
+//     ingestion gateway endpoint, a moniker and an ingestion auth token, and
+//   - the ingestion gateway endpoint, which accepts compressed batches authenticated with
+//     that token.
+//
+// Together with a bridge built with the mock_auth Cargo feature (which skips MSI and
+// certificate authentication against GCS), the full exporter runs offline against it.
+package genevaemulator // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuregigwarmexporter/testbed/genevaemulator"
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ConfigPathMarker identifies GCS configuration requests
+	// (GET /api/agent/v3/{environment}/{account}/MonitoringStorageKeys/?Namespace=...).
+	ConfigPathMarker = "/MonitoringStorageKeys"
+	// IngestPath is the ingestion gateway upload path.
+	IngestPath = "/api/v1/ingestion/ingest"
+
+	// DefaultMoniker is the storage account moniker returned by the config endpoint.
+	DefaultMoniker = "emulatordiagaccount"
+	// DefaultTokenTTL is the lifetime of issued ingestion tokens.
+	DefaultTokenTTL = time.Hour
+	// DefaultRetainUploads is the number of accepted uploads kept for Uploads.
+	DefaultRetainUploads = 1000
+)
+
+// Upload is one batch accepted by the ingestion endpoint.
+type Upload struct {
+	// Event is the Geneva event (table) name.
+	Event string
+	// Namespace and Moniker identify the destination.
+	Namespace string
+	Moniker   string
+	// Format is the payload format, e.g. "centralbond/lz4hc".
+	Format string
+	// SchemaIDs are the schema IDs of the rows in the payload.
+	SchemaIDs string
+	// StartTime and EndTime are the time range of the rows, as sent by the uploader.
+	StartTime string
+	EndTime   string
+	// Body is the compressed payload.
+	Body []byte
+	// ReceivedAt is when the upload was accepted.
+	ReceivedAt time.Time
+}
+
+// Config configures the emulator.
+type Config struct {
+	// Endpoint is the listen address, e.g. "127.0.0.1:0".
+	Endpoint string
+	// Moniker is returned by the config endpoint (default: DefaultMoniker).
+	Moniker string
+	// TokenTTL is the lifetime of issued ingestion tokens (default: DefaultTokenTTL).
+	TokenTTL time.Duration
+	// OnUpload, if set, is called for every accepted upload. It must not block for long.
+	OnUpload func(Upload)
+	// RetainUploads is the number of most recent accepted uploads kept for Uploads
+	// (default: DefaultRetainUploads). A negative value keeps none, e.g. when OnUpload
+	// consumes the uploads; AcceptedUploads still counts them.
+	RetainUploads int
+	// Faults configures the faults injected into requests (default: none).
+	Faults FaultProfile
+}
+
+// Emulator is a local Geneva backend.
+type Emulator struct {
+	cfg      Config
+	listener net.Listener
+	server   *http.Server
+	faults   *faultInjector
+
+	mu             sync.Mutex
+	tokens         map[string]time.Time
+	configRequests int
+	uploads        []Upload
+	accepted       int
+	rejected       int
+}
+
+// New creates an emulator. Call Start to begin serving.
+func New(cfg Config) *Emulator {
+	if cfg.Moniker == "" {
+		cfg.Moniker = DefaultMoniker
+	}
+	if cfg.TokenTTL == 0 {
+		cfg.TokenTTL = DefaultTokenTTL
+	}
+	if cfg.RetainUploads == 0 {
+		cfg.RetainUploads = DefaultRetainUploads
+	}
+	e := &Emulator{cfg: cfg, tokens: make(map[string]time.Time), faults: newFaultInjector(cfg.Faults)}
+	mux := http.NewServeMux()
+	mux.HandleFunc(IngestPath, e.handleIngest)
+	mux.HandleFunc("/", e.handleConfig)
+	e.server = &http.Server{Handler: e.faults.wrap(e, mux), ReadHeaderTimeout: 10 * time.Second}
+	return e
+}
+
+// Start listens on the configured endpoint and serves in the background.
+func (e *Emulator) Start() error {
+	if err := e.cfg.Faults.Validate(); err != nil {
+		return fmt.Errorf("invalid fault profile: %w", err)
+	}
+	ln, err := net.Listen("tcp", e.cfg.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", e.cfg.Endpoint, err)
+	}
+	e.listener = ln
+	e.faults.start(time.Now())
+	go func() {
+		_ = e.server.Serve(ln)
+	}()
+	return nil
+}
+
+// Stop shuts the emulator down.
+func (e *Emulator) Stop(ctx context.Context) error {
+	if e.listener == nil {
+		return nil
+	}
+	return e.server.Shutdown(ctx)
+}
+
+// URL returns the base URL of the emulator, to be used as the exporter endpoint.
+func (e *Emulator) URL() string {
+	if e.listener == nil {
+		return "http://" + e.cfg.Endpoint
+	}
+	return "http://" + e.listener.Addr().String()
+}
+
+// ConfigRequests returns the number of GCS configuration requests served.
+func (e *Emulator) ConfigRequests() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.configRequests
+}
+
+// Uploads returns a copy of the most recent accepted uploads, at most RetainUploads.
+func (e *Emulator) Uploads() []Upload {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Upload(nil), e.uploads...)
+}
+
+// AcceptedUploads returns the number of accepted uploads, including those no longer
+// retained.
+func (e *Emulator) AcceptedUploads() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.accepted
+}
+
+// RejectedUploads returns the number of uploads rejected for missing or invalid tokens
+// or malformed requests.
+func (e *Emulator) RejectedUploads() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.rejected
+}
+
+// Faults returns the number of injected faults per kind (FaultOutage, FaultServerError, ...).
+func (e *Emulator) Faults() map[string]int {
+	return e.faults.faults()
+}
+
+// configResponse mirrors the GCS MonitoringStorageKeys response.
+type configResponse struct {
+	IngestionGatewayInfo ingestionGatewayInfo `json:"IngestionGatewayInfo"`
+	StorageAccountKeys   []storageAccountKey  `json:"StorageAccountKeys"`
+	TagID                string               `json:"TagId"`
+}
+
+type ingestionGatewayInfo struct {
+	Endpoint            string `json:"Endpoint"`
+	AuthToken           string `json:"AuthToken"`
+	AuthTokenExpiryTime string `json:"AuthTokenExpiryTime"`
+}
+
+type storageAccountKey struct {
+	AccountMonikerName string `json:"AccountMonikerName"`
+	AccountGroupName   string `json:"AccountGroupName"`
+	IsPrimaryMoniker   bool   `json:"IsPrimaryMoniker"`
+}
+
+// handleConfig serves GCS configuration requests.
+func (e *Emulator) handleConfig(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet || !strings.Contains(req.URL.Path, ConfigPathMarker) {
+		http.NotFound(w, req)
+		return
+	}
+	query := req.URL.Query()
+	for _, param := range []string{"Namespace", "Region"} {
+		if query.Get(param) == "" {
+			http.Error(w, fmt.Sprintf("missing query parameter %q", param), http.StatusBadRequest)
+			return
+		}
+	}
+
+	gateway := "http://" + req.Host
+	expiry := time.Now().Add(e.cfg.TokenTTL)
+	token, err := newToken(gateway, expiry)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	e.mu.Lock()
+	e.configRequests++
+	e.tokens[token] = expiry
+	e.mu.Unlock()
+
+	resp := configResponse{
+		IngestionGatewayInfo: ingestionGatewayInfo{
+			Endpoint:            gateway,
+			AuthToken:           token,
+			AuthTokenExpiryTime: expiry.UTC().Format(time.RFC3339),
+		},
+		StorageAccountKeys: []storageAccountKey{{
+			AccountMonikerName: e.cfg.Moniker,
+			AccountGroupName:   e.cfg.Moniker + "group",
+			IsPrimaryMoniker:   true,
+		}},
+		TagID: "emulator",
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handleIngest accepts uploads that carry a valid, unexpired token.
+func (e *Emulator) handleIngest(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || !e.validToken(token) {
+		e.reject()
+		http.Error(w, "invalid or expired ingestion token", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		e.reject()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := req.URL.Query()
+	upload := Upload{
+		Event:      query.Get("event"),
+		Namespace:  query.Get("namespace"),
+		Moniker:    query.Get("moniker"),
+		Format:     query.Get("format"),
+		SchemaIDs:  query.Get("schemaIds"),
+		StartTime:  query.Get("startTime"),
+		EndTime:    query.Get("endTime"),
+		Body:       body,
+		ReceivedAt: time.Now(),
+	}
+	if err := validateUpload(upload, query.Get("dataSize")); err != nil {
+		e.reject()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	e.retain(upload)
+	if e.cfg.OnUpload != nil {
+		e.cfg.OnUpload(upload)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]string{"ticket": newTicket()})
+}
+
+// retain counts an accepted upload and keeps it for Uploads, dropping the oldest retained
+// upload once RetainUploads are kept.
+func (e *Emulator) retain(upload Upload) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.accepted++
+	if e.cfg.RetainUploads < 0 {
+		return
+	}
+	if len(e.uploads) == e.cfg.RetainUploads {
+		e.uploads[0] = Upload{}
+		e.uploads = e.uploads[1:]
+	}
+	e.uploads = append(e.uploads, upload)
+}
+
+// validateUpload checks the query parameters the ingestion gateway requires.
+func validateUpload(u Upload, dataSize string) error {
+	if u.Event == "" {
+		return errors.New(`missing query parameter "event"`)
+	}
+	if u.Namespace == "" {
+		return errors.New(`missing query parameter "namespace"`)
+	}
+	if dataSize != "" {
+		n, err := strconv.Atoi(dataSize)
+		if err != nil || n != len(u.Body) {
+			return fmt.Errorf("dataSize %q does not match body size %d", dataSize, len(u.Body))
+		}
+	}
+	return nil
+}
+
+func (e *Emulator) validToken(token string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	expiry, ok := e.tokens[token]
+	return ok && time.Now().Before(expiry)
+}
+
+// revokeToken invalidates an issued token.
+func (e *Emulator) revokeToken(token string) {
+	e.mu.Lock()
+	delete(e.tokens, token)
+	e.mu.Unlock()
+}
+
+func (e *Emulator) reject() {
+	e.mu.Lock()
+	e.rejected++
+	e.mu.Unlock()
+}
+
+// newToken returns an unsigned JWT whose claims carry the ingestion endpoint and expiry,
+// as the uploader reads the endpoint from the token.
+func newToken(endpoint string, expiry time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"Endpoint": endpoint,
+		"exp":      expiry.Unix(),
+		"jti":      newTicket(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return header + "." + base64.RawURLEncoding.EncodeToString(claims) + ".", nil
+}
+
+func newTicket() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
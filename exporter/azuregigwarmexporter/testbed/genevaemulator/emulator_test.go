@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genevaemulator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetainUploads(t *testing.T) {
+	events := func(uploads []Upload) []string {
+		var out []string
+		for _, u := range uploads {
+			out = append(out, u.Event)
+		}
+		return out
+	}
+
+	e := New(Config{RetainUploads: 2})
+	for _, event := range []string{"A", "B", "C"} {
+		e.retain(Upload{Event: event})
+	}
+	assert.Equal(t, []string{"B", "C"}, events(e.Uploads()), "the oldest uploads are dropped")
+	assert.Equal(t, 3, e.AcceptedUploads())
+
+	e = New(Config{RetainUploads: -1})
+	e.retain(Upload{Event: "A"})
+	assert.Empty(t, e.Uploads())
+	assert.Equal(t, 1, e.AcceptedUploads())
+
+	assert.Equal(t, DefaultRetainUploads, New(Config{}).cfg.RetainUploads)
+}
@@ -0,0 +1,312 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genevaemulator // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuregigwarmexporter/testbed/genevaemulator"
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Latency distributions.
+const (
+	// LatencyFixed delays every request by Min.
+	LatencyFixed = "fixed"
+	// LatencyUniform delays requests by a duration drawn uniformly from [Min, Max].
+	LatencyUniform = "uniform"
+	// LatencyExponential delays requests by Min plus an exponentially distributed duration
+	// with mean Mean, capped at Max if set.
+	LatencyExponential = "exponential"
+)
+
+// Fault kinds, as reported by Emulator.Faults.
+const (
+	FaultOutage       = "outage"
+	FaultServerError  = "server_error"
+	FaultThrottle     = "throttle"
+	FaultReset        = "reset"
+	FaultTokenFailure = "token_failure"
+	FaultAuthFailure  = "auth_failure"
+)
+
+// defaultRetryAfter is the Retry-After of throttled responses when FaultProfile.RetryAfter is unset.
+const defaultRetryAfter = time.Second
+
+// FaultProfile configures the faults the emulator injects. The zero value injects none.
+//
+// Rates are probabilities in [0, 1] drawn independently for every request. At most one of
+// the server error, throttle, reset and token failure faults is injected per upload, so
+// their rates must not add up to more than 1; the same holds for the reset and auth
+// failure rates of configuration requests.
+type FaultProfile struct {
+	// Seed seeds the random source, so a profile injects the same sequence of faults for
+	// the same sequence of requests. Zero uses a random seed.
+	Seed uint64
+	// Latency delays every request.
+	Latency Latency
+	// ServerErrorRate is the fraction of uploads answered with 503 Service Unavailable.
+	ServerErrorRate float64
+	// ThrottleRate is the fraction of uploads answered with 429 Too Many Requests.
+	ThrottleRate float64
+	// RetryAfter is the Retry-After of throttled responses (default: 1s).
+	RetryAfter time.Duration
+	// ResetRate is the fraction of requests whose connection is reset without a response.
+	ResetRate float64
+	// TokenFailureRate is the fraction of uploads answered with 401 Unauthorized. The
+	// token of the upload is revoked, so the uploader has to fetch a new one.
+	TokenFailureRate float64
+	// AuthFailureRate is the fraction of configuration (GCS) requests answered with
+	// 401 Unauthorized.
+	AuthFailureRate float64
+	// Outages are windows, relative to Start, during which every request is answered with
+	// 503 Service Unavailable.
+	Outages []Outage
+}
+
+// Latency is a request latency distribution.
+type Latency struct {
+	// Distribution is "fixed", "uniform" or "exponential". Empty disables latency.
+	Distribution string
+	Min          time.Duration
+	Max          time.Duration
+	Mean         time.Duration
+}
+
+// Outage is a window during which the emulator is unavailable.
+type Outage struct {
+	// Start is the offset of the outage from the start of the emulator.
+	Start time.Duration
+	// Duration is the length of the outage.
+	Duration time.Duration
+}
+
+// Validate checks if the fault profile is valid.
+func (p *FaultProfile) Validate() error {
+	rates := map[string]float64{
+		"ServerErrorRate":  p.ServerErrorRate,
+		"ThrottleRate":     p.ThrottleRate,
+		"ResetRate":        p.ResetRate,
+		"TokenFailureRate": p.TokenFailureRate,
+		"AuthFailureRate":  p.AuthFailureRate,
+	}
+	for name, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("invalid %s %v (must be in [0, 1])", name, rate)
+		}
+	}
+	if sum := p.ServerErrorRate + p.ThrottleRate + p.ResetRate + p.TokenFailureRate; sum > 1 {
+		return fmt.Errorf("upload fault rates add up to %v (must be <= 1)", sum)
+	}
+	if sum := p.ResetRate + p.AuthFailureRate; sum > 1 {
+		return fmt.Errorf("configuration fault rates add up to %v (must be <= 1)", sum)
+	}
+	if p.RetryAfter < 0 {
+		return fmt.Errorf("invalid RetryAfter %v (must be >= 0)", p.RetryAfter)
+	}
+	if err := p.Latency.validate(); err != nil {
+		return err
+	}
+	for i, o := range p.Outages {
+		if o.Start < 0 || o.Duration <= 0 {
+			return fmt.Errorf("invalid outage %d: start %v, duration %v", i, o.Start, o.Duration)
+		}
+	}
+	return nil
+}
+
+func (l *Latency) validate() error {
+	if l.Min < 0 || l.Max < 0 || l.Mean < 0 {
+		return errors.New("latency durations must be >= 0")
+	}
+	switch l.Distribution {
+	case "", LatencyFixed:
+	case LatencyUniform:
+		if l.Max < l.Min {
+			return fmt.Errorf("uniform latency: Max %v is less than Min %v", l.Max, l.Min)
+		}
+	case LatencyExponential:
+		if l.Mean == 0 {
+			return errors.New("exponential latency requires Mean")
+		}
+	default:
+		return fmt.Errorf("unknown latency distribution %q", l.Distribution)
+	}
+	return nil
+}
+
+// faultInjector decides which faults to inject into requests.
+type faultInjector struct {
+	profile FaultProfile
+	started time.Time
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	counts map[string]int
+}
+
+func newFaultInjector(p FaultProfile) *faultInjector {
+	seed := p.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	if p.RetryAfter == 0 {
+		p.RetryAfter = defaultRetryAfter
+	}
+	return &faultInjector{
+		profile: p,
+		rng:     rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+		counts:  make(map[string]int),
+	}
+}
+
+// start marks the start of the emulator, which outage windows are relative to.
+func (f *faultInjector) start(now time.Time) {
+	f.mu.Lock()
+	f.started = now
+	f.mu.Unlock()
+}
+
+// float64 returns a random value in [0, 1).
+func (f *faultInjector) float64() float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rng.Float64()
+}
+
+func (f *faultInjector) record(kind string) {
+	f.mu.Lock()
+	f.counts[kind]++
+	f.mu.Unlock()
+}
+
+// delay returns the latency of a request.
+func (f *faultInjector) delay() time.Duration {
+	l := f.profile.Latency
+	switch l.Distribution {
+	case LatencyFixed:
+		return l.Min
+	case LatencyUniform:
+		return l.Min + time.Duration(f.float64()*float64(l.Max-l.Min))
+	case LatencyExponential:
+		f.mu.Lock()
+		d := l.Min + time.Duration(f.rng.ExpFloat64()*float64(l.Mean))
+		f.mu.Unlock()
+		if l.Max > 0 && d > l.Max {
+			d = l.Max
+		}
+		return d
+	default:
+		return 0
+	}
+}
+
+// inOutage reports whether now falls into an outage window.
+func (f *faultInjector) inOutage(now time.Time) bool {
+	f.mu.Lock()
+	elapsed := now.Sub(f.started)
+	f.mu.Unlock()
+	for _, o := range f.profile.Outages {
+		if elapsed >= o.Start && elapsed < o.Start+o.Duration {
+			return true
+		}
+	}
+	return false
+}
+
+// pick draws one of kinds with the probabilities in rates, or returns "" for no fault.
+func (f *faultInjector) pick(kinds []string, rates []float64) string {
+	u := f.float64()
+	for i, rate := range rates {
+		if u < rate {
+			return kinds[i]
+		}
+		u -= rate
+	}
+	return ""
+}
+
+// wrap injects faults into the requests served by next. Uploads and configuration
+// requests that are answered with a fault never reach next, so they are not recorded.
+func (f *faultInjector) wrap(e *Emulator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if d := f.delay(); d > 0 {
+			select {
+			case <-time.After(d):
+			case <-req.Context().Done():
+				return
+			}
+		}
+		if f.inOutage(time.Now()) {
+			f.record(FaultOutage)
+			http.Error(w, "service unavailable (emulated outage)", http.StatusServiceUnavailable)
+			return
+		}
+
+		p := f.profile
+		var fault string
+		if req.URL.Path == IngestPath {
+			fault = f.pick(
+				[]string{FaultServerError, FaultThrottle, FaultReset, FaultTokenFailure},
+				[]float64{p.ServerErrorRate, p.ThrottleRate, p.ResetRate, p.TokenFailureRate})
+		} else if strings.Contains(req.URL.Path, ConfigPathMarker) {
+			fault = f.pick([]string{FaultReset, FaultAuthFailure}, []float64{p.ResetRate, p.AuthFailureRate})
+		}
+		if fault != "" {
+			f.record(fault)
+		}
+
+		switch fault {
+		case FaultServerError:
+			http.Error(w, "service unavailable (emulated)", http.StatusServiceUnavailable)
+		case FaultThrottle:
+			w.Header().Set("Retry-After", strconv.Itoa(int((p.RetryAfter+time.Second-1)/time.Second)))
+			http.Error(w, "too many requests (emulated)", http.StatusTooManyRequests)
+		case FaultReset:
+			resetConnection(w)
+		case FaultTokenFailure:
+			if token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+				e.revokeToken(token)
+			}
+			http.Error(w, "ingestion token rejected (emulated)", http.StatusUnauthorized)
+		case FaultAuthFailure:
+			http.Error(w, "authentication failed (emulated)", http.StatusUnauthorized)
+		default:
+			next.ServeHTTP(w, req)
+		}
+	})
+}
+
+// faults returns the number of injected faults per kind.
+func (f *faultInjector) faults() map[string]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]int, len(f.counts))
+	for k, v := range f.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// resetConnection closes the connection of w with a TCP reset instead of a response.
+func resetConnection(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection reset not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+}
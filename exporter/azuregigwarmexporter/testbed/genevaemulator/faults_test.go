@@ -0,0 +1,211 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genevaemulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultProfileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile FaultProfile
+		wantErr string
+	}{
+		{name: "Zero"},
+		{
+			name: "Valid",
+			profile: FaultProfile{
+				Seed:             1,
+				Latency:          Latency{Distribution: LatencyExponential, Min: time.Millisecond, Mean: 5 * time.Millisecond, Max: time.Second},
+				ServerErrorRate:  0.25,
+				ThrottleRate:     0.25,
+				ResetRate:        0.25,
+				TokenFailureRate: 0.25,
+				AuthFailureRate:  0.75,
+				RetryAfter:       2 * time.Second,
+				Outages:          []Outage{{Start: 0, Duration: time.Second}},
+			},
+		},
+		{
+			name:    "NegativeRate",
+			profile: FaultProfile{ThrottleRate: -0.1},
+			wantErr: "invalid ThrottleRate -0.1",
+		},
+		{
+			name:    "RateAboveOne",
+			profile: FaultProfile{AuthFailureRate: 1.5},
+			wantErr: "invalid AuthFailureRate 1.5",
+		},
+		{
+			name:    "UploadRatesAboveOne",
+			profile: FaultProfile{ServerErrorRate: 0.5, TokenFailureRate: 0.75},
+			wantErr: "upload fault rates add up to 1.25",
+		},
+		{
+			name:    "ConfigurationRatesAboveOne",
+			profile: FaultProfile{ResetRate: 0.5, AuthFailureRate: 0.75},
+			wantErr: "configuration fault rates add up to 1.25",
+		},
+		{
+			name:    "NegativeRetryAfter",
+			profile: FaultProfile{RetryAfter: -time.Second},
+			wantErr: "invalid RetryAfter",
+		},
+		{
+			name:    "NegativeLatency",
+			profile: FaultProfile{Latency: Latency{Distribution: LatencyFixed, Min: -time.Millisecond}},
+			wantErr: "latency durations must be >= 0",
+		},
+		{
+			name:    "UniformMaxBelowMin",
+			profile: FaultProfile{Latency: Latency{Distribution: LatencyUniform, Min: time.Second, Max: time.Millisecond}},
+			wantErr: "uniform latency: Max 1ms is less than Min 1s",
+		},
+		{
+			name:    "ExponentialWithoutMean",
+			profile: FaultProfile{Latency: Latency{Distribution: LatencyExponential, Min: time.Millisecond}},
+			wantErr: "exponential latency requires Mean",
+		},
+		{
+			name:    "UnknownDistribution",
+			profile: FaultProfile{Latency: Latency{Distribution: "normal"}},
+			wantErr: `unknown latency distribution "normal"`,
+		},
+		{
+			name:    "NegativeOutageStart",
+			profile: FaultProfile{Outages: []Outage{{Start: -time.Second, Duration: time.Second}}},
+			wantErr: "invalid outage 0",
+		},
+		{
+			name:    "EmptyOutage",
+			profile: FaultProfile{Outages: []Outage{{Start: 0, Duration: time.Second}, {Start: time.Second}}},
+			wantErr: "invalid outage 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// uploadFaults draws the faults of n uploads from f.
+func uploadFaults(f *faultInjector, n int) []string {
+	p := f.profile
+	out := make([]string, n)
+	for i := range out {
+		out[i] = f.pick(
+			[]string{FaultServerError, FaultThrottle, FaultReset, FaultTokenFailure},
+			[]float64{p.ServerErrorRate, p.ThrottleRate, p.ResetRate, p.TokenFailureRate})
+	}
+	return out
+}
+
+func TestFaultInjectorSeeded(t *testing.T) {
+	profile := FaultProfile{
+		Seed:            42,
+		Latency:         Latency{Distribution: LatencyUniform, Min: time.Millisecond, Max: 100 * time.Millisecond},
+		ServerErrorRate: 0.2,
+		ThrottleRate:    0.2,
+		ResetRate:       0.2,
+	}
+
+	a, b := newFaultInjector(profile), newFaultInjector(profile)
+	assert.Equal(t, uploadFaults(a, 200), uploadFaults(b, 200), "the same seed injects the same faults")
+	for range 20 {
+		assert.Equal(t, a.delay(), b.delay(), "the same seed draws the same latencies")
+	}
+
+	other := profile
+	other.Seed = 43
+	assert.NotEqual(t, uploadFaults(newFaultInjector(profile), 200), uploadFaults(newFaultInjector(other), 200),
+		"another seed injects other faults")
+}
+
+func TestFaultInjectorPick(t *testing.T) {
+	const draws = 100000
+	f := newFaultInjector(FaultProfile{Seed: 7, ServerErrorRate: 0.1, ThrottleRate: 0.3, TokenFailureRate: 0.2})
+	counts := make(map[string]int)
+	for _, fault := range uploadFaults(f, draws) {
+		counts[fault]++
+	}
+
+	// The rates partition [0, 1): every kind is drawn with its own rate, and no fault
+	// with the remaining probability
+	assert.InDelta(t, 0.1, float64(counts[FaultServerError])/draws, 0.01)
+	assert.InDelta(t, 0.3, float64(counts[FaultThrottle])/draws, 0.01)
+	assert.InDelta(t, 0.2, float64(counts[FaultTokenFailure])/draws, 0.01)
+	assert.InDelta(t, 0.4, float64(counts[""])/draws, 0.01)
+	assert.Zero(t, counts[FaultReset], "a zero rate is never drawn")
+
+	f = newFaultInjector(FaultProfile{Seed: 7, ServerErrorRate: 0.5, ResetRate: 0.5})
+	assert.NotContains(t, uploadFaults(f, 1000), "", "rates adding up to 1 always inject a fault")
+}
+
+func TestFaultInjectorOutage(t *testing.T) {
+	f := newFaultInjector(FaultProfile{Outages: []Outage{
+		{Start: time.Second, Duration: 2 * time.Second},
+		{Start: 10 * time.Second, Duration: time.Second},
+	}})
+	start := time.Now()
+	f.start(start)
+
+	tests := []struct {
+		offset time.Duration
+		want   bool
+	}{
+		{0, false},
+		{time.Second - time.Nanosecond, false},
+		{time.Second, true},
+		{3*time.Second - time.Nanosecond, true},
+		{3 * time.Second, false},
+		{10 * time.Second, true},
+		{11 * time.Second, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, f.inOutage(start.Add(tt.offset)), "offset %v", tt.offset)
+	}
+}
+
+func TestFaultInjectorDelay(t *testing.T) {
+	assert.Zero(t, newFaultInjector(FaultProfile{}).delay())
+
+	fixed := newFaultInjector(FaultProfile{Latency: Latency{Distribution: LatencyFixed, Min: 5 * time.Millisecond}})
+	assert.Equal(t, 5*time.Millisecond, fixed.delay())
+
+	uniform := newFaultInjector(FaultProfile{Seed: 1, Latency: Latency{Distribution: LatencyUniform, Min: time.Millisecond, Max: 3 * time.Millisecond}})
+	for range 1000 {
+		d := uniform.delay()
+		assert.GreaterOrEqual(t, d, time.Millisecond)
+		assert.LessOrEqual(t, d, 3*time.Millisecond)
+	}
+
+	exponential := newFaultInjector(FaultProfile{Seed: 1, Latency: Latency{
+		Distribution: LatencyExponential,
+		Min:          time.Millisecond,
+		Mean:         10 * time.Millisecond,
+		Max:          20 * time.Millisecond,
+	}})
+	capped := 0
+	for range 1000 {
+		d := exponential.delay()
+		assert.GreaterOrEqual(t, d, time.Millisecond)
+		assert.LessOrEqual(t, d, 20*time.Millisecond)
+		if d == 20*time.Millisecond {
+			capped++
+		}
+	}
+	assert.Positive(t, capped, "long delays are capped at Max")
+}
@@ -4,6 +4,7 @@ go 1.24
 
 require (
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.137.0
+	github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter v0.0.0
 	go.opentelemetry.io/collector/component v1.41.0
 	go.opentelemetry.io/collector/consumer v1.41.0
 	go.opentelemetry.io/collector/pdata v1.41.0
@@ -11,3 +12,6 @@ require (
 
 // Use local contrib testbed if needed
 replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/common => ../../../internal/common
+
+// The decoder of uploaded payloads lives in the exporter module
+replace github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter => ../
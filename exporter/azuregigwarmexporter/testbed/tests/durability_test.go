@@ -0,0 +1,278 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/testbed/testbed"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuregigwarmexporter/testbed/genevaemulator"
+	"go.opentelemetry.io/collector/testbed/datareceivers"
+)
+
+// recordIDAttribute carries the unique ID the recording senders stamp on every span and
+// log record, so that the received data can be matched against what was sent.
+const recordIDAttribute = "test.record_id"
+
+// TestGigWarmCrashRestartTraces asserts at-least-once delivery of spans when the
+// collector is killed mid-stream and restarted on the same file_storage directory.
+func TestGigWarmCrashRestartTraces(t *testing.T) {
+	sender := &recordingTraceSender{
+		TraceDataSender: testbed.NewOTLPTraceDataSender(testbed.DefaultHost, testutil.GetAvailablePort(t)),
+		idRecorder:      newIDRecorder(),
+	}
+	scenarioCrashRestart(t, sender, sender.idRecorder, func(backend *testbed.MockBackend) map[string]int {
+		received := map[string]int{}
+		for _, td := range backend.ReceivedTraces {
+			forEachSpanAttributes(td, func(attrs pcommon.Map) { countID(t, received, attrs) })
+		}
+		return received
+	})
+}
+
+// TestGigWarmCrashRestartLogs asserts at-least-once delivery of log records when the
+// collector is killed mid-stream and restarted on the same file_storage directory.
+func TestGigWarmCrashRestartLogs(t *testing.T) {
+	sender := &recordingLogSender{
+		LogDataSender: testbed.NewOTLPLogsDataSender(testbed.DefaultHost, testutil.GetAvailablePort(t)),
+		idRecorder:    newIDRecorder(),
+	}
+	scenarioCrashRestart(t, sender, sender.idRecorder, func(backend *testbed.MockBackend) map[string]int {
+		received := map[string]int{}
+		for _, ld := range backend.ReceivedLogs {
+			forEachLogAttributes(ld, func(attrs pcommon.Map) { countID(t, received, attrs) })
+		}
+		return received
+	})
+}
+
+// scenarioCrashRestart sends load through a collector with a file_storage backed sending
+// queue, kills the collector with SIGKILL while uploads are slow enough to keep a backlog
+// in the queue, restarts it on the same storage directory and checks that every record
+// the collector accepted reached the emulator at least once. The duplicate rate is logged.
+func scenarioCrashRestart(
+	t *testing.T,
+	sender testbed.DataSender,
+	recorder *idRecorder,
+	receivedIDs func(*testbed.MockBackend) map[string]int,
+) {
+	if testing.Short() {
+		t.Skip("Skipping crash-restart scenario in short mode")
+	}
+
+	receiver := datareceivers.NewAzureGigWarmDataReceiver(testutil.GetAvailablePort(t)).(*datareceivers.AzureGigWarmDataReceiver)
+	// Slow uploads keep records in the persistent queue when the collector is killed.
+	receiver.WithFaultProfile(genevaemulator.FaultProfile{
+		Latency: genevaemulator.Latency{Distribution: genevaemulator.LatencyFixed, Min: 200 * time.Millisecond},
+	}).WithRetry(faultRetry).WithQueue(faultQueue)
+
+	options := testbed.LoadOptions{
+		DataItemsPerSecond: 1000,
+		ItemsPerBatch:      10,
+		Parallel:           1,
+	}
+	configStr := createFileStorageConfigYaml(t, sender, receiver, t.TempDir())
+
+	agentProc := testbed.NewChildProcessCollector(testbed.WithEnvVar("GOMAXPROCS", "2"))
+	configCleanup, err := agentProc.PrepareConfig(t, configStr)
+	require.NoError(t, err)
+	defer configCleanup()
+
+	tc := testbed.NewTestCase(
+		t,
+		testbed.NewPerfTestDataProvider(options),
+		sender,
+		receiver,
+		agentProc,
+		&testbed.PerfTestValidator{},
+		performanceResultsSummary,
+		// Resource monitoring gives access to the collector process, which is killed below.
+		testbed.WithResourceLimits(testbed.ResourceSpec{ExpectedMaxCPU: 400, ExpectedMaxRAM: 2000}),
+	)
+	defer tc.Stop()
+	tc.MockBackend.EnableRecording()
+
+	tc.StartBackend()
+	tc.StartAgent()
+	tc.StartLoad(options)
+	tc.WaitFor(func() bool { return tc.MockBackend.DataItemsReceived() > 0 }, "first upload received")
+	tc.Sleep(5 * time.Second)
+
+	tc.WaitFor(func() bool { return agentProc.GetProcessMon() != nil }, "collector process monitored")
+	require.NoError(t, agentProc.GetProcessMon().Kill())
+	// Stop reaps the killed process. Its error only reports the kill.
+	_, _ = agentProc.Stop()
+	accepted := recorder.count()
+	t.Logf("killed collector: accepted=%d received=%d", accepted, tc.MockBackend.DataItemsReceived())
+
+	// Load keeps running while the collector is down; the failed sends are not recorded.
+	restarted := testbed.NewChildProcessCollector(testbed.WithEnvVar("GOMAXPROCS", "2"))
+	restartCleanup, err := restarted.PrepareConfig(t, configStr)
+	require.NoError(t, err)
+	defer restartCleanup()
+	require.NoError(t, restarted.Start(testbed.StartParams{
+		Name:        "Agent-restarted",
+		LogFilePath: filepath.Join("results", t.Name(), "agent-restarted.log"),
+	}))
+	defer func() { _, _ = restarted.Stop() }()
+
+	tc.WaitFor(func() bool { return recorder.count() > accepted }, "restarted collector accepting data")
+	tc.Sleep(10 * time.Second)
+	tc.StopLoad()
+
+	tc.WaitForN(func() bool {
+		return tc.MockBackend.DataItemsReceived() >= uint64(recorder.count())
+	}, 2*time.Minute, "all accepted records received")
+	// Let retries of uploads that were in flight during the kill land before counting.
+	tc.Sleep(5 * time.Second)
+	_, _ = restarted.Stop()
+	tc.StopBackend()
+
+	received := receivedIDs(tc.MockBackend)
+	var missing []string
+	for id := range recorder.ids() {
+		if received[id] == 0 {
+			missing = append(missing, id)
+		}
+	}
+	total := 0
+	for _, n := range received {
+		total += n
+	}
+	duplicates := total - len(received)
+	t.Logf("sent=%d received=%d unique=%d duplicates=%d duplicate_rate=%.2f%%",
+		recorder.count(), total, len(received), duplicates, 100*float64(duplicates)/float64(max(len(received), 1)))
+
+	require.Empty(t, missing, "%d records accepted by the collector were never delivered", len(missing))
+	require.Zero(t, receiver.DecodeErrors(), "uploads failed to decode")
+	require.Zero(t, receiver.ConsumeErrors(), "decoded uploads were not forwarded")
+}
+
+// idRecorder assigns unique record IDs and remembers the ones the collector accepted.
+type idRecorder struct {
+	mu       sync.Mutex
+	next     int64
+	accepted map[string]struct{}
+}
+
+func newIDRecorder() *idRecorder {
+	return &idRecorder{accepted: map[string]struct{}{}}
+}
+
+// assign stamps attrs with a new record ID, unless a failed send already did, and
+// returns the ID.
+func (r *idRecorder) assign(attrs pcommon.Map) string {
+	if v, ok := attrs.Get(recordIDAttribute); ok {
+		return v.AsString()
+	}
+	r.mu.Lock()
+	r.next++
+	id := r.next
+	r.mu.Unlock()
+	attrs.PutInt(recordIDAttribute, id)
+	return strconv.FormatInt(id, 10)
+}
+
+func (r *idRecorder) accept(ids []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		r.accepted[id] = struct{}{}
+	}
+}
+
+func (r *idRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.accepted)
+}
+
+func (r *idRecorder) ids() map[string]struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make(map[string]struct{}, len(r.accepted))
+	for id := range r.accepted {
+		ids[id] = struct{}{}
+	}
+	return ids
+}
+
+// recordingTraceSender stamps every span with a record ID and records the IDs of the
+// spans the collector accepted.
+type recordingTraceSender struct {
+	testbed.TraceDataSender
+	*idRecorder
+}
+
+func (s *recordingTraceSender) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	var ids []string
+	forEachSpanAttributes(td, func(attrs pcommon.Map) { ids = append(ids, s.assign(attrs)) })
+	if err := s.TraceDataSender.ConsumeTraces(ctx, td); err != nil {
+		return err
+	}
+	s.accept(ids)
+	return nil
+}
+
+// recordingLogSender stamps every log record with a record ID and records the IDs of
+// the records the collector accepted.
+type recordingLogSender struct {
+	testbed.LogDataSender
+	*idRecorder
+}
+
+func (s *recordingLogSender) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	var ids []string
+	forEachLogAttributes(ld, func(attrs pcommon.Map) { ids = append(ids, s.assign(attrs)) })
+	if err := s.LogDataSender.ConsumeLogs(ctx, ld); err != nil {
+		return err
+	}
+	s.accept(ids)
+	return nil
+}
+
+func forEachSpanAttributes(td ptrace.Traces, fn func(pcommon.Map)) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		scopes := td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopes.Len(); j++ {
+			spans := scopes.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				fn(spans.At(k).Attributes())
+			}
+		}
+	}
+}
+
+func forEachLogAttributes(ld plog.Logs, fn func(pcommon.Map)) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		scopes := ld.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < scopes.Len(); j++ {
+			records := scopes.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				fn(records.At(k).Attributes())
+			}
+		}
+	}
+}
+
+// countID counts the record ID found in attrs.
+func countID(t *testing.T, received map[string]int, attrs pcommon.Map) {
+	v, ok := attrs.Get(recordIDAttribute)
+	if !ok {
+		t.Errorf("received a record without %s", recordIDAttribute)
+		return
+	}
+	received[v.AsString()]++
+}
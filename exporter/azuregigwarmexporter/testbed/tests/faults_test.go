@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/testbed/testbed"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuregigwarmexporter/testbed/genevaemulator"
+	"go.opentelemetry.io/collector/testbed/datareceivers"
+)
+
+// faultRetry retries failed exports until they succeed, so that no data is dropped while
+// the emulator injects faults.
+const faultRetry = `
+    retry_on_failure:
+      enabled: true
+      initial_interval: 100ms
+      max_interval: 1s
+      max_elapsed_time: 0s
+`
+
+// faultQueue persists the sending queue in the file_storage extension.
+const faultQueue = `
+    sending_queue:
+      enabled: true
+      num_consumers: 4
+      queue_size: 10000
+      storage: file_storage
+`
+
+// faultProfiles are the fault profiles every signal is tested with. Each profile names
+// the fault kinds it must have injected for the scenario to count.
+var faultProfiles = []struct {
+	name     string
+	profile  genevaemulator.FaultProfile
+	expected []string
+}{
+	{
+		name: "Latency",
+		profile: genevaemulator.FaultProfile{
+			Latency: genevaemulator.Latency{Distribution: genevaemulator.LatencyExponential, Min: 5 * time.Millisecond, Mean: 50 * time.Millisecond, Max: 2 * time.Second},
+		},
+	},
+	{
+		name:     "ServerErrors",
+		profile:  genevaemulator.FaultProfile{Seed: 1, ServerErrorRate: 0.2},
+		expected: []string{genevaemulator.FaultServerError},
+	},
+	{
+		name:     "Throttling",
+		profile:  genevaemulator.FaultProfile{Seed: 2, ThrottleRate: 0.2, RetryAfter: time.Second},
+		expected: []string{genevaemulator.FaultThrottle},
+	},
+	{
+		name:     "ConnectionResets",
+		profile:  genevaemulator.FaultProfile{Seed: 3, ResetRate: 0.1},
+		expected: []string{genevaemulator.FaultReset},
+	},
+	{
+		name:     "TokenFailures",
+		profile:  genevaemulator.FaultProfile{Seed: 4, TokenFailureRate: 0.1, AuthFailureRate: 0.3},
+		expected: []string{genevaemulator.FaultTokenFailure},
+	},
+	{
+		name: "Outage",
+		profile: genevaemulator.FaultProfile{
+			Outages: []genevaemulator.Outage{{Start: 5 * time.Second, Duration: 10 * time.Second}},
+		},
+		expected: []string{genevaemulator.FaultOutage},
+	},
+	{
+		name: "Mixed",
+		profile: genevaemulator.FaultProfile{
+			Seed:             5,
+			Latency:          genevaemulator.Latency{Distribution: genevaemulator.LatencyUniform, Min: time.Millisecond, Max: 200 * time.Millisecond},
+			ServerErrorRate:  0.05,
+			ThrottleRate:     0.05,
+			ResetRate:        0.05,
+			TokenFailureRate: 0.05,
+			Outages:          []genevaemulator.Outage{{Start: 10 * time.Second, Duration: 5 * time.Second}},
+		},
+	},
+}
+
+// TestGigWarmFaultTracesNoDataLoss asserts that every span is delivered while the
+// Geneva emulator injects faults, with a persistent sending queue.
+func TestGigWarmFaultTracesNoDataLoss(t *testing.T) {
+	for _, fp := range faultProfiles {
+		t.Run(fp.name, func(t *testing.T) {
+			scenarioNoDataLossUnderFaults(
+				t,
+				testbed.NewOTLPTraceDataSender(testbed.DefaultHost, testutil.GetAvailablePort(t)),
+				fp.profile,
+				fp.expected,
+			)
+		})
+	}
+}
+
+// TestGigWarmFaultLogsNoDataLoss asserts that every log record is delivered while
+// the Geneva emulator injects faults, with a persistent sending queue.
+func TestGigWarmFaultLogsNoDataLoss(t *testing.T) {
+	for _, fp := range faultProfiles {
+		t.Run(fp.name, func(t *testing.T) {
+			scenarioNoDataLossUnderFaults(
+				t,
+				testbed.NewOTLPLogsDataSender(testbed.DefaultHost, testutil.GetAvailablePort(t)),
+				fp.profile,
+				fp.expected,
+			)
+		})
+	}
+}
+
+// scenarioNoDataLossUnderFaults sends load for a while through a collector whose exporter
+// uses a file_storage backed sending queue, and waits until the emulator has received
+// every data item that was sent.
+func scenarioNoDataLossUnderFaults(t *testing.T, sender testbed.DataSender, profile genevaemulator.FaultProfile, expected []string) {
+	if testing.Short() {
+		t.Skip("Skipping fault injection scenario in short mode")
+	}
+
+	receiver := datareceivers.NewAzureGigWarmDataReceiver(testutil.GetAvailablePort(t)).(*datareceivers.AzureGigWarmDataReceiver)
+	receiver.WithFaultProfile(profile).WithRetry(faultRetry).WithQueue(faultQueue)
+
+	options := testbed.LoadOptions{
+		DataItemsPerSecond: 1000,
+		ItemsPerBatch:      10,
+		Parallel:           1,
+	}
+	agentProc := testbed.NewChildProcessCollector(testbed.WithEnvVar("GOMAXPROCS", "2"))
+	configStr := createFileStorageConfigYaml(t, sender, receiver, t.TempDir())
+	configCleanup, err := agentProc.PrepareConfig(t, configStr)
+	require.NoError(t, err)
+	defer configCleanup()
+
+	tc := testbed.NewTestCase(
+		t,
+		testbed.NewPerfTestDataProvider(options),
+		sender,
+		receiver,
+		agentProc,
+		&testbed.PerfTestValidator{},
+		performanceResultsSummary,
+	)
+	defer tc.Stop()
+
+	tc.StartBackend()
+	tc.StartAgent()
+	tc.StartLoad(options)
+	tc.WaitFor(func() bool { return tc.LoadGenerator.DataItemsSent() > 0 }, "load generator started")
+	tc.Sleep(20 * time.Second)
+	tc.StopLoad()
+
+	tc.WaitForN(func() bool {
+		return tc.MockBackend.DataItemsReceived() >= tc.LoadGenerator.DataItemsSent()
+	}, 2*time.Minute, "all data items received")
+	tc.StopAgent()
+
+	sent := tc.LoadGenerator.DataItemsSent()
+	received := tc.MockBackend.DataItemsReceived()
+	faults := receiver.Emulator().Faults()
+	t.Logf("sent=%d received=%d duplicates=%d faults=%v", sent, received, int64(received)-int64(sent), faults)
+
+	require.GreaterOrEqual(t, received, sent, "data items were lost")
+	require.Zero(t, receiver.DecodeErrors(), "uploads failed to decode")
+	require.Zero(t, receiver.ConsumeErrors(), "decoded uploads were not forwarded")
+	for _, kind := range expected {
+		require.Positive(t, faults[kind], "no %q faults were injected", kind)
+	}
+}
+
+// createFileStorageConfigYaml returns a collector config with a single pipeline from
+// sender to receiver and a file_storage extension in storageDir.
+func createFileStorageConfigYaml(t *testing.T, sender testbed.DataSender, receiver testbed.DataReceiver, storageDir string) string {
+	var pipeline string
+	switch sender.(type) {
+	case testbed.TraceDataSender:
+		pipeline = "traces"
+	case testbed.LogDataSender:
+		pipeline = "logs"
+	default:
+		t.Fatalf("unsupported sender %T", sender)
+	}
+
+	return fmt.Sprintf(`
+receivers:%v
+exporters:%v
+
+extensions:
+  file_storage:
+    directory: %s
+
+service:
+  telemetry:
+    metrics:
+      level: none
+  extensions: [file_storage]
+  pipelines:
+    %s:
+      receivers: [%v]
+      exporters: [%v]
+`,
+		sender.GenConfigYAMLStr(),
+		receiver.GenConfigYAMLStr(),
+		filepath.ToSlash(storageDir),
+		pipeline,
+		sender.ProtocolName(),
+		receiver.ProtocolName(),
+	)
+}
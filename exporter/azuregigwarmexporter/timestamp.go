@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// TimestampOutOfRangeClamp moves timestamps outside the window to its nearest edge.
+	TimestampOutOfRangeClamp = "clamp"
+	// TimestampOutOfRangeDrop drops records with timestamps outside the window.
+	TimestampOutOfRangeDrop = "drop"
+
+	// Values of the "action" attribute of the timestamp actions metric.
+	timestampActionObserved      = "fallback_observed"
+	timestampActionEnd           = "fallback_end"
+	timestampActionNow           = "fallback_now"
+	timestampActionClampedPast   = "clamped_past"
+	timestampActionClampedFuture = "clamped_future"
+	timestampActionDroppedPast   = "dropped_past"
+	timestampActionDroppedFuture = "dropped_future"
+)
+
+// TimestampConfig configures normalization of log record and span timestamps before encoding.
+type TimestampConfig struct {
+	// Enabled turns timestamp normalization on (default: false).
+	Enabled bool `mapstructure:"enabled"`
+	// MaxPast is the maximum age of a timestamp. Older timestamps are out of range.
+	// Zero disables the check.
+	MaxPast time.Duration `mapstructure:"max_past"`
+	// MaxFuture is the maximum distance of a timestamp into the future. Later timestamps
+	// are out of range. Zero disables the check.
+	MaxFuture time.Duration `mapstructure:"max_future"`
+	// OutOfRange is "clamp" or "drop" (default: "clamp").
+	OutOfRange string `mapstructure:"out_of_range"`
+}
+
+// Validate checks if the timestamp configuration is valid.
+func (c *TimestampConfig) Validate() error {
+	if c.MaxPast < 0 {
+		return fmt.Errorf("invalid max_past: %v (must be >= 0)", c.MaxPast)
+	}
+	if c.MaxFuture < 0 {
+		return fmt.Errorf("invalid max_future: %v (must be >= 0)", c.MaxFuture)
+	}
+	switch c.OutOfRange {
+	case "", TimestampOutOfRangeClamp, TimestampOutOfRangeDrop:
+	default:
+		return fmt.Errorf(`invalid out_of_range %q (must be %q or %q)`, c.OutOfRange, TimestampOutOfRangeClamp, TimestampOutOfRangeDrop)
+	}
+	return nil
+}
+
+// timestampActions holds the number of records per normalization action.
+type timestampActions map[string]int64
+
+// timestampNormalizer applies the timestamp policy to pdata in place.
+type timestampNormalizer struct {
+	maxPast   time.Duration
+	maxFuture time.Duration
+	drop      bool
+	now       func() time.Time
+}
+
+// newTimestampNormalizer creates a normalizer for cfg, or returns nil if normalization is disabled.
+func newTimestampNormalizer(cfg TimestampConfig) *timestampNormalizer {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &timestampNormalizer{
+		maxPast:   cfg.MaxPast,
+		maxFuture: cfg.MaxFuture,
+		drop:      cfg.OutOfRange == TimestampOutOfRangeDrop,
+		now:       time.Now,
+	}
+}
+
+// window returns the range of accepted timestamps relative to now. A zero bound is open.
+func (n *timestampNormalizer) window(now time.Time) (lower, upper pcommon.Timestamp) {
+	if n.maxPast > 0 {
+		lower = pcommon.NewTimestampFromTime(now.Add(-n.maxPast))
+	}
+	if n.maxFuture > 0 {
+		upper = pcommon.NewTimestampFromTime(now.Add(n.maxFuture))
+	}
+	return lower, upper
+}
+
+// check returns the in-range timestamp for ts, and whether the record is kept. Actions
+// taken are added to actions.
+func (n *timestampNormalizer) check(ts, lower, upper pcommon.Timestamp, actions timestampActions) (pcommon.Timestamp, bool) {
+	switch {
+	case lower != 0 && ts < lower:
+		if n.drop {
+			actions[timestampActionDroppedPast]++
+			return ts, false
+		}
+		actions[timestampActionClampedPast]++
+		return lower, true
+	case upper != 0 && ts > upper:
+		if n.drop {
+			actions[timestampActionDroppedFuture]++
+			return ts, false
+		}
+		actions[timestampActionClampedFuture]++
+		return upper, true
+	}
+	return ts, true
+}
+
+// normalizeLogs fills in missing log record timestamps from ObservedTimestamp or the current
+// time, then clamps or drops records outside the window.
+func (n *timestampNormalizer) normalizeLogs(ld plog.Logs) timestampActions {
+	actions := make(timestampActions)
+	now := n.now()
+	nowTS := pcommon.NewTimestampFromTime(now)
+	lower, upper := n.window(now)
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			rl.ScopeLogs().At(j).LogRecords().RemoveIf(func(lr plog.LogRecord) bool {
+				ts := lr.Timestamp()
+				if ts == 0 {
+					if ts = lr.ObservedTimestamp(); ts != 0 {
+						actions[timestampActionObserved]++
+					} else {
+						ts = nowTS
+						actions[timestampActionNow]++
+					}
+				}
+				ts, keep := n.check(ts, lower, upper, actions)
+				if !keep {
+					return true
+				}
+				lr.SetTimestamp(ts)
+				return false
+			})
+		}
+	}
+	return actions
+}
+
+// normalizeTraces fills in missing span start times from the end time or the current time,
+// then clamps or drops spans whose start time is outside the window. Clamped spans are
+// shifted as a whole so that their duration is preserved.
+func (n *timestampNormalizer) normalizeTraces(td ptrace.Traces) timestampActions {
+	actions := make(timestampActions)
+	now := n.now()
+	nowTS := pcommon.NewTimestampFromTime(now)
+	lower, upper := n.window(now)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			rs.ScopeSpans().At(j).Spans().RemoveIf(func(span ptrace.Span) bool {
+				start, end := span.StartTimestamp(), span.EndTimestamp()
+				if start == 0 {
+					if end != 0 {
+						start = end
+						actions[timestampActionEnd]++
+					} else {
+						start = nowTS
+						actions[timestampActionNow]++
+					}
+				}
+				if end < start {
+					end = start
+				}
+				normalized, keep := n.check(start, lower, upper, actions)
+				if !keep {
+					return true
+				}
+				span.SetStartTimestamp(normalized)
+				span.SetEndTimestamp(normalized + (end - start))
+				return false
+			})
+		}
+	}
+	return actions
+}
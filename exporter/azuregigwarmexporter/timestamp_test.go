@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azuregigwarmexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var testNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestTimestampNormalizer(outOfRange string) *timestampNormalizer {
+	n := newTimestampNormalizer(TimestampConfig{
+		Enabled:    true,
+		MaxPast:    time.Hour,
+		MaxFuture:  time.Minute,
+		OutOfRange: outOfRange,
+	})
+	n.now = func() time.Time { return testNow }
+	return n
+}
+
+func ts(d time.Duration) pcommon.Timestamp {
+	return pcommon.NewTimestampFromTime(testNow.Add(d))
+}
+
+func TestNormalizeLogs(t *testing.T) {
+	tests := []struct {
+		name       string
+		outOfRange string
+		timestamp  pcommon.Timestamp
+		observed   pcommon.Timestamp
+		want       pcommon.Timestamp
+		dropped    bool
+		action     string
+	}{
+		{name: "InRange", timestamp: ts(-time.Minute), want: ts(-time.Minute)},
+		{name: "FallbackObserved", observed: ts(-time.Second), want: ts(-time.Second), action: timestampActionObserved},
+		{name: "FallbackNow", want: ts(0), action: timestampActionNow},
+		{name: "ClampPast", timestamp: ts(-2 * time.Hour), want: ts(-time.Hour), action: timestampActionClampedPast},
+		{name: "ClampFuture", timestamp: ts(time.Hour), want: ts(time.Minute), action: timestampActionClampedFuture},
+		{name: "DropPast", outOfRange: TimestampOutOfRangeDrop, timestamp: ts(-2 * time.Hour), dropped: true, action: timestampActionDroppedPast},
+		{name: "DropFuture", outOfRange: TimestampOutOfRangeDrop, timestamp: ts(time.Hour), dropped: true, action: timestampActionDroppedFuture},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestTimestampNormalizer(tt.outOfRange)
+			ld := plog.NewLogs()
+			lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+			lr.SetTimestamp(tt.timestamp)
+			lr.SetObservedTimestamp(tt.observed)
+
+			actions := n.normalizeLogs(ld)
+			if tt.dropped {
+				assert.Zero(t, ld.LogRecordCount())
+			} else {
+				require.Equal(t, 1, ld.LogRecordCount())
+				assert.Equal(t, tt.want, lr.Timestamp())
+			}
+			want := timestampActions{}
+			if tt.action != "" {
+				want[tt.action] = 1
+			}
+			assert.Equal(t, want, actions)
+		})
+	}
+}
+
+func TestNormalizeTraces(t *testing.T) {
+	tests := []struct {
+		name       string
+		outOfRange string
+		start, end pcommon.Timestamp
+		wantStart  pcommon.Timestamp
+		wantEnd    pcommon.Timestamp
+		dropped    bool
+		action     string
+	}{
+		{name: "InRange", start: ts(-time.Minute), end: ts(0), wantStart: ts(-time.Minute), wantEnd: ts(0)},
+		{name: "FallbackEnd", end: ts(-time.Second), wantStart: ts(-time.Second), wantEnd: ts(-time.Second), action: timestampActionEnd},
+		{name: "FallbackNow", wantStart: ts(0), wantEnd: ts(0), action: timestampActionNow},
+		{name: "EndBeforeStart", start: ts(-time.Minute), end: ts(-time.Hour), wantStart: ts(-time.Minute), wantEnd: ts(-time.Minute)},
+		{
+			name:      "ClampPastKeepsDuration",
+			start:     ts(-3 * time.Hour),
+			end:       ts(-3*time.Hour + 5*time.Second),
+			wantStart: ts(-time.Hour),
+			wantEnd:   ts(-time.Hour + 5*time.Second),
+			action:    timestampActionClampedPast,
+		},
+		{
+			name:      "ClampFutureKeepsDuration",
+			start:     ts(time.Hour),
+			end:       ts(time.Hour + time.Second),
+			wantStart: ts(time.Minute),
+			wantEnd:   ts(time.Minute + time.Second),
+			action:    timestampActionClampedFuture,
+		},
+		{name: "DropPast", outOfRange: TimestampOutOfRangeDrop, start: ts(-2 * time.Hour), dropped: true, action: timestampActionDroppedPast},
+		{name: "DropFuture", outOfRange: TimestampOutOfRangeDrop, start: ts(time.Hour), dropped: true, action: timestampActionDroppedFuture},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestTimestampNormalizer(tt.outOfRange)
+			td := ptrace.NewTraces()
+			span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			span.SetStartTimestamp(tt.start)
+			span.SetEndTimestamp(tt.end)
+
+			actions := n.normalizeTraces(td)
+			if tt.dropped {
+				assert.Zero(t, td.SpanCount())
+			} else {
+				require.Equal(t, 1, td.SpanCount())
+				assert.Equal(t, tt.wantStart, span.StartTimestamp())
+				assert.Equal(t, tt.wantEnd, span.EndTimestamp())
+			}
+			want := timestampActions{}
+			if tt.action != "" {
+				want[tt.action] = 1
+			}
+			assert.Equal(t, want, actions)
+		})
+	}
+}
+
+func TestNormalizeOpenWindow(t *testing.T) {
+	n := newTimestampNormalizer(TimestampConfig{Enabled: true})
+	require.NotNil(t, n)
+	ld := plog.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetTimestamp(1)
+
+	assert.Empty(t, n.normalizeLogs(ld))
+	assert.Equal(t, pcommon.Timestamp(1), lr.Timestamp(), "zero bounds accept any timestamp")
+}
+
+func TestTimestampConfigValidate(t *testing.T) {
+	assert.Nil(t, newTimestampNormalizer(TimestampConfig{MaxPast: time.Hour}))
+
+	tests := []struct {
+		name    string
+		cfg     TimestampConfig
+		wantErr string
+	}{
+		{name: "Valid", cfg: TimestampConfig{MaxPast: time.Hour, OutOfRange: TimestampOutOfRangeDrop}},
+		{name: "NegativeMaxPast", cfg: TimestampConfig{MaxPast: -time.Second}, wantErr: "invalid max_past"},
+		{name: "NegativeMaxFuture", cfg: TimestampConfig{MaxFuture: -time.Second}, wantErr: "invalid max_future"},
+		{name: "InvalidOutOfRange", cfg: TimestampConfig{OutOfRange: "shift"}, wantErr: `invalid out_of_range "shift"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
 	cgogeneva "github.com/open-telemetry/otel-azuregigwarm-exporter/exporter/azuregigwarmexporter/internal/cgo"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"go.opentelemetry.io/otel/attribute"
@@ -23,11 +25,18 @@ import (
 
 // tracesExporter implements the traces exporter for Azure Geneva Warm (GigWarm) via Rust FFI.
 type tracesExporter struct {
-	params    exporter.Settings
-	cfg       *Config
-	client    *cgogeneva.GenevaClient
-	logger    *zap.Logger
-	telemetry *telemetry
+	params     exporter.Settings
+	cfg        *Config
+	client     genevaClient
+	logger     *zap.Logger
+	telemetry  *telemetry
+	router     *eventNameRouter
+	schema     *schemaMapper
+	redactor   *redactor
+	enricher   *enricher
+	spans      *spanTransformer
+	timestamps *timestampNormalizer
+	dumper     *batchDumper
 }
 
 // tracesExporter no longer needs to implement consumer.Traces or component.Component
@@ -66,23 +75,40 @@ func newTracesExporter(_ context.Context, set exporter.Settings, cfg *Config) (*
 		cgoCfg.CertPassword = cfg.CertPassword
 	}
 
-    // Add workload identity resource if needed
-    if cfg.AuthMethod == WorkloadIdentity {
-        cgoCfg.WorkloadIdentityResource = cfg.WorkloadIdentityResource
-    }
+	// Add workload identity resource if needed
+	if cfg.AuthMethod == WorkloadIdentity {
+		cgoCfg.WorkloadIdentityResource = cfg.WorkloadIdentityResource
+	}
+
+	redactor, err := newRedactor(cfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
 
+	dumper, err := acquireBatchDumper(cfg.DebugDump)
+	if err != nil {
+		return nil, err
+	}
 
 	client, err := cgogeneva.NewGenevaClient(cgoCfg)
 	if err != nil {
+		dumper.release()
 		return nil, fmt.Errorf("failed to create Geneva FFI client: %w", err)
 	}
 
 	return &tracesExporter{
-		params:    set,
-		cfg:       cfg,
-		client:    client,
-		logger:    set.Logger,
-		telemetry: telemetryInst,
+		params:     set,
+		cfg:        cfg,
+		client:     ffiClient{client: client},
+		logger:     set.Logger,
+		telemetry:  telemetryInst,
+		router:     newEventNameRouter(cfg.EventName),
+		schema:     newSchemaMapper(cfg.Schema),
+		redactor:   redactor,
+		enricher:   newEnricher(cfg.Enrichment, cfg.RoleName, cfg.RoleInstance),
+		spans:      newSpanTransformer(cfg.SpanEvents, cfg.SpanLinks),
+		timestamps: newTimestampNormalizer(cfg.Timestamps),
+		dumper:     dumper,
 	}, nil
 }
 
@@ -95,16 +121,50 @@ func (e *tracesExporter) start(_ context.Context, _ component.Host) error {
 		zap.String("namespace", e.cfg.Namespace),
 		zap.String("region", e.cfg.Region),
 	)
-	return nil
+	if e.cfg.DryRun {
+		e.logger.Warn("Dry run is enabled: data is encoded but never uploaded to Geneva Warm")
+	}
+	return e.telemetry.startBridgeStats()
 }
 
 // shutdown is called by the Collector when the exporter is shutting down.
-func (e *tracesExporter) shutdown(_ context.Context) error {
+// In-flight encodes and uploads are drained, up to the context deadline, before the
+// Geneva client handle is freed.
+func (e *tracesExporter) shutdown(ctx context.Context) error {
 	e.logger.Info("Shutting down AzureGigWarm traces exporter")
+	// Drain in-flight uploads first, so that their batches can still be dumped and their
+	// bridge statistics are still reported
+	var err error
 	if e.client != nil {
-		e.client.Close()
+		if err = e.client.Shutdown(ctx); err != nil {
+			e.logger.Warn("Geneva client did not drain before shutdown deadline", zap.Error(err))
+		}
+	}
+	e.dumper.release()
+	if err := e.telemetry.stopBridgeStats(); err != nil {
+		e.logger.Warn("Failed to unregister Rust bridge metrics", zap.Error(err))
+	}
+	return err
+}
+
+// applyPolicies applies the record policies to td in place. It runs once per request,
+// before the request is queued (see policyTraces).
+func (e *tracesExporter) applyPolicies(ctx context.Context, td ptrace.Traces) {
+	spanAttrs := e.getCommonAttributes()
+
+	// Fill in missing timestamps and clamp or drop out-of-range spans
+	if e.timestamps != nil {
+		e.telemetry.recordTimestampActions(ctx, e.timestamps.normalizeTraces(td), spanAttrs...)
+		if td.SpanCount() == 0 {
+			e.logger.Debug("All spans dropped by timestamp policy")
+			return
+		}
+	}
+
+	// Redact sensitive data before routing, so that routing and schema mapping only see redacted values
+	if e.redactor != nil {
+		e.telemetry.recordRedactions(ctx, e.redactor.redactTraces(td), spanAttrs...)
 	}
-	return nil
 }
 
 // pushTraces implements the push function for exporterhelper and sends traces via Rust FFI.
@@ -124,8 +184,113 @@ func (e *tracesExporter) pushTraces(ctx context.Context, td ptrace.Traces) error
 		zap.Int("span_count", spanCount),
 		zap.Int("resource_spans", td.ResourceSpans().Len()))
 
+	// Transform a copy, so that failed chunks are handed back for retry as received and a
+	// retry never maps already mapped spans
+	prepared := ptrace.NewTraces()
+	td.CopyTo(prepared)
+
+	// Span events are exported as rows per chunk, from the request as received
+	if e.spans != nil {
+		e.spans.transformTraces(prepared)
+	}
+
+	// Add role and host metadata to spans that do not set it
+	if e.enricher != nil {
+		e.enricher.enrichTraces(prepared)
+	}
+
+	// Route spans to Geneva events before schema mapping renames or drops their attributes
+	var eventNames spanEventNames
+	if e.router != nil {
+		eventNames = e.router.routeTraces(prepared)
+	}
+
+	// Map attributes onto the Geneva table schema
+	if e.schema != nil {
+		e.schema.mapTraces(prepared)
+	}
+
+	// Split oversized requests so that each encoder call stays within the configured limits,
+	// and spans routed to different events so that each encoder call writes one event
+	plan, chunkEvents := planTracesByEvent(prepared, e.cfg.MaxRecordsPerRequest, e.cfg.MaxRequestBytes, eventNames)
+	chunks := plan.chunks()
+	if chunks > 1 {
+		e.logger.Debug("Split traces request into chunks",
+			zap.Int("span_count", spanCount),
+			zap.Int("chunks", chunks))
+	}
+
+	failedPhase := ""
+	var total exportStats
+	exportChunk := func(i int, chunk ptrace.Traces) error {
+		stats, phase, err := e.exportTracesChunk(ctx, chunk, chunkEvents[i], spanAttrs)
+		if err != nil && failedPhase == "" {
+			failedPhase = phase
+		}
+		total.add(stats)
+		return err
+	}
+	var failed int
+	var err error
+	if e.spans != nil {
+		// Upload the event rows of each chunk together with its spans, so that a failed
+		// chunk never hands back events whose rows already went out
+		failed, err = e.spans.exportChunks(td, prepared, plan, func(rows plog.Logs) error {
+			err := e.exportSpanEventRows(ctx, rows)
+			if err != nil && failedPhase == "" {
+				failedPhase = "span_events"
+			}
+			return err
+		}, exportChunk)
+	} else {
+		failed, err = exportTracesChunks(td, prepared, plan, exportChunk, nil)
+	}
+	if err != nil {
+		// Record trace export failure (once per pushTraces call)
+		e.telemetry.recordTracesExportError(ctx, append(traceAttrs,
+			attribute.String("error", failedPhase+"_failed"),
+			attribute.String("phase", phaseOf(failedPhase)))...)
+
+		if failed < chunks {
+			// Only the failed chunks are handed back to exporterhelper for retry
+			e.logger.Warn("Partially failed to export spans to Geneva Warm",
+				zap.Int("failed_chunks", failed),
+				zap.Int("total_chunks", chunks),
+			)
+		}
+		return err
+	}
+
+	if e.cfg.DryRun {
+		e.logger.Info("Dry run: encoded spans without uploading",
+			zap.Int("span_count", total.records),
+			zap.Int("chunks", chunks),
+			zap.Int("batches", total.batches),
+			zap.Int("bytes", total.bytes),
+		)
+		return nil
+	}
+
+	// Record trace export success - recorded only once per successful trace export
+	e.telemetry.recordTracesExported(ctx, traceAttrs...)
+
+	e.logger.Debug("Successfully uploaded spans to Geneva Warm",
+		zap.Int("span_count", spanCount),
+		zap.Int("chunks", chunks),
+		zap.Int("batches", total.batches),
+	)
+	return nil
+}
+
+// exportTracesChunk marshals, encodes and uploads one chunk of a traces request. The spans
+// are written to eventName, or to the encoder's fixed Span event when it is empty. It
+// returns the failed step ("marshal", "encoding" or "upload") on error. In dry-run mode
+// the chunk is encoded but not uploaded.
+func (e *tracesExporter) exportTracesChunk(ctx context.Context, chunk ptrace.Traces, eventName string, spanAttrs []attribute.KeyValue) (exportStats, string, error) {
+	spanCount := chunk.SpanCount()
+
 	// Marshal to OTLP ExportTraceServiceRequest protobuf bytes
-	req := ptraceotlp.NewExportRequestFromTraces(td)
+	req := ptraceotlp.NewExportRequestFromTraces(chunk)
 	data, err := req.MarshalProto()
 	if err != nil {
 		// Record failure
@@ -133,16 +298,16 @@ func (e *tracesExporter) pushTraces(ctx context.Context, td ptrace.Traces) error
 			attribute.String("error", "marshal_failed"),
 			attribute.String("phase", "encoding"))...)
 
-		// Record trace export failure
-		e.telemetry.recordTracesExportError(ctx, append(traceAttrs,
-			attribute.String("error", "marshal_failed"),
-			attribute.String("phase", "encoding"))...)
-
-		return fmt.Errorf("failed to marshal traces to protobuf: %w", err)
+		return exportStats{}, "marshal", fmt.Errorf("failed to marshal traces to protobuf: %w", err)
 	}
 
 	// Encode once, then upload each batch synchronously via FFI.
-	batches, err := e.client.EncodeAndCompressSpans(data)
+	var batches encodedBatches
+	if eventName != "" {
+		batches, err = e.client.EncodeAndCompressSpansAs(data, eventName)
+	} else {
+		batches, err = e.client.EncodeAndCompressSpans(data)
+	}
 	if err != nil {
 		e.logger.Error("Failed to encode spans for Geneva Warm", zap.Error(err))
 		// Record failure
@@ -150,16 +315,30 @@ func (e *tracesExporter) pushTraces(ctx context.Context, td ptrace.Traces) error
 			attribute.String("error", "encoding_failed"),
 			attribute.String("phase", "encoding"))...)
 
-		// Record trace export failure
-		e.telemetry.recordTracesExportError(ctx, append(traceAttrs,
-			attribute.String("error", "encoding_failed"),
-			attribute.String("phase", "encoding"))...)
-
-		return fmt.Errorf("failed to encode spans for Geneva Warm: %w", err)
+		return exportStats{}, "encoding", fmt.Errorf("failed to encode spans for Geneva Warm: %w", err)
 	}
 	defer batches.Close()
 
+	if e.dumper != nil {
+		if err := e.dumper.dumpEncodedBatches("traces", batches, spanShapes(chunk, eventName)); err != nil {
+			e.logger.Warn("Failed to dump encoded batches", zap.Error(err))
+		}
+	}
+
 	n := batches.Len()
+	size, err := batches.Size()
+	if err != nil {
+		e.logger.Warn("Failed to read encoded batch sizes", zap.Error(err))
+	}
+	stats := exportStats{records: spanCount, batches: n, bytes: size}
+
+	if e.cfg.DryRun {
+		e.logger.Debug("Dry run: skipping upload of encoded spans",
+			zap.Int("span_count", spanCount),
+			zap.Int("batches", n),
+			zap.Int("bytes", size))
+		return stats, "", nil
+	}
 
 	// Upload batches with retry logic
 	if err := e.uploadBatchesWithRetry(ctx, batches, n); err != nil {
@@ -168,33 +347,100 @@ func (e *tracesExporter) pushTraces(ctx context.Context, td ptrace.Traces) error
 			attribute.String("error", "upload_failed"),
 			attribute.String("phase", "upload"))...)
 
-		// Record trace export failure
-		e.telemetry.recordTracesExportError(ctx, append(traceAttrs,
-			attribute.String("error", "upload_failed"),
-			attribute.String("phase", "upload"))...)
-
-		return err
+		return exportStats{}, "upload", err
 	}
 
-	// Record success - metrics recorded only once per successful trace export
+	// Record success - metrics recorded only once per successfully exported chunk
 	e.telemetry.recordSpansExported(ctx, int64(spanCount), spanAttrs...)
 
-	// Record trace export success - recorded only once per successful trace export
-	e.telemetry.recordTracesExported(ctx, traceAttrs...)
-
 	e.logger.Debug("Recording spans exported",
 		zap.Int("span_count", spanCount),
-		zap.Int("resource_spans", td.ResourceSpans().Len()))
+		zap.Int("resource_spans", chunk.ResourceSpans().Len()))
 
-	e.logger.Debug("Successfully uploaded spans to Geneva Warm",
-		zap.Int("span_count", spanCount),
-		zap.Int("batches", n),
-	)
+	return stats, "", nil
+}
+
+// exportSpanEventRows encodes span event rows with the logs encoder, so that they are
+// written to their own Geneva event, and uploads them. The rows are split with the same
+// limits as spans. On error the remaining chunks are skipped, since the traces request is
+// retried as a whole.
+func (e *tracesExporter) exportSpanEventRows(ctx context.Context, rows plog.Logs) error {
+	if e.enricher != nil {
+		e.enricher.enrichLogs(rows)
+	}
+
+	plan := planLogs(rows, e.cfg.MaxRecordsPerRequest, e.cfg.MaxRequestBytes)
+	var total exportStats
+	for i := 0; i < plan.chunks(); i++ {
+		stats, err := e.exportSpanEventRowsChunk(ctx, logsChunk(rows, plan, i))
+		if err != nil {
+			return err
+		}
+		total.add(stats)
+	}
+
+	if e.cfg.DryRun {
+		e.logger.Info("Dry run: encoded span events without uploading",
+			zap.Int("event_count", total.records),
+			zap.Int("chunks", plan.chunks()),
+			zap.Int("batches", total.batches),
+			zap.Int("bytes", total.bytes))
+		return nil
+	}
+
+	e.logger.Debug("Uploaded span events to Geneva Warm",
+		zap.Int("event_count", total.records),
+		zap.Int("chunks", plan.chunks()),
+		zap.Int("batches", total.batches))
 	return nil
 }
 
+// exportSpanEventRowsChunk marshals, encodes and uploads one chunk of span event rows. In
+// dry-run mode the chunk is encoded but not uploaded.
+func (e *tracesExporter) exportSpanEventRowsChunk(ctx context.Context, rows plog.Logs) (exportStats, error) {
+	data, err := plogotlp.NewExportRequestFromLogs(rows).MarshalProto()
+	if err != nil {
+		return exportStats{}, fmt.Errorf("failed to marshal span events to protobuf: %w", err)
+	}
+
+	batches, err := e.client.EncodeAndCompressLogs(data)
+	if err != nil {
+		e.logger.Error("Failed to encode span events for Geneva Warm", zap.Error(err))
+		return exportStats{}, fmt.Errorf("failed to encode span events for Geneva Warm: %w", err)
+	}
+	defer batches.Close()
+
+	if e.dumper != nil {
+		if err := e.dumper.dumpEncodedBatches("span_events", batches, logShapes(rows)); err != nil {
+			e.logger.Warn("Failed to dump encoded batches", zap.Error(err))
+		}
+	}
+
+	size, err := batches.Size()
+	if err != nil {
+		e.logger.Warn("Failed to read encoded batch sizes", zap.Error(err))
+	}
+	stats := exportStats{records: rows.LogRecordCount(), batches: batches.Len(), bytes: size}
+	if e.cfg.DryRun {
+		return stats, nil
+	}
+
+	if err := e.uploadBatchesWithRetry(ctx, batches, batches.Len()); err != nil {
+		return exportStats{}, fmt.Errorf("failed to upload span events: %w", err)
+	}
+	return stats, nil
+}
+
+// phaseOf maps a failed export step to the "phase" telemetry attribute value.
+func phaseOf(step string) string {
+	if step == "upload" || step == "span_events" {
+		return "upload"
+	}
+	return "encoding"
+}
+
 // uploadBatchesWithRetry uploads batches concurrently and retries failed batches
-func (e *tracesExporter) uploadBatchesWithRetry(ctx context.Context, batches *cgogeneva.EncodedBatches, n int) error {
+func (e *tracesExporter) uploadBatchesWithRetry(ctx context.Context, batches encodedBatches, n int) error {
 	type batchResult struct {
 		index int
 		err   error
@@ -261,7 +507,7 @@ func (e *tracesExporter) getTraceAttributes(td ptrace.Traces) []attribute.KeyVal
 }
 
 // uploadBatchWithRetry uploads a single batch with exponential backoff retry
-func (e *tracesExporter) uploadBatchWithRetry(ctx context.Context, batches *cgogeneva.EncodedBatches, index int) error {
+func (e *tracesExporter) uploadBatchWithRetry(ctx context.Context, batches encodedBatches, index int) error {
 	// Use common attributes for batch metrics (basic exporter attributes without trace-specific data)
 	batchAttrs := e.getCommonAttributes()
 
@@ -299,6 +545,8 @@ func (e *tracesExporter) uploadBatchWithRetry(ctx context.Context, batches *cgog
 
 	var lastErr error
 	backoff := initialInterval
+	attempts := 0
+	failureReason := "max_retries_exceeded"
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		// Check context cancellation
@@ -309,6 +557,7 @@ func (e *tracesExporter) uploadBatchWithRetry(ctx context.Context, batches *cgog
 		}
 
 		// Attempt upload
+		attempts++
 		err := e.client.UploadBatch(batches, index)
 		if err == nil {
 			// Success
@@ -339,6 +588,17 @@ func (e *tracesExporter) uploadBatchWithRetry(ctx context.Context, batches *cgog
 			break
 		}
 
+		// Stop retrying if the export deadline expires before the next attempt could start
+		if !hasTimeForRetry(ctx, backoff) {
+			e.logger.Warn("Remaining export deadline too short for another batch upload attempt",
+				zap.Int("batch_index", index),
+				zap.Int("attempts", attempts),
+				zap.Duration("backoff", backoff),
+			)
+			failureReason = "deadline_exceeded"
+			break
+		}
+
 		// Sleep with backoff
 		select {
 		case <-ctx.Done():
@@ -358,18 +618,19 @@ func (e *tracesExporter) uploadBatchWithRetry(ctx context.Context, batches *cgog
 
 	e.logger.Error("Failed to upload batch after all retries",
 		zap.Int("batch_index", index),
-		zap.Int("attempts", maxRetries+1),
+		zap.Int("attempts", attempts),
+		zap.String("reason", failureReason),
 		zap.Error(lastErr),
 	)
 
 	// Record batch failure
 	e.telemetry.recordBatchExportError(ctx, append(batchAttrs,
-		attribute.String("error", "max_retries_exceeded"),
+		attribute.String("error", failureReason),
 		attribute.Bool("retry_enabled", true),
-		attribute.Int("attempts", maxRetries+1))...)
+		attribute.Int("attempts", attempts))...)
 
-	return fmt.Errorf("failed to upload spans batch %d after %d attempts: %w", index, maxRetries+1, lastErr)
+	return fmt.Errorf("failed to upload spans batch %d after %d attempts: %w", index, attempts, lastErr)
 }
 
 // These interface methods are no longer needed because exporterhelper wraps the exporter
-// and handles the consumer.Traces and component.Component interfaces
\ No newline at end of file
+// and handles the consumer.Traces and component.Component interfaces